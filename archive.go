@@ -0,0 +1,288 @@
+package minio
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/minio/minio-go/v7"
+
+	"github.com/beyondstorage/go-storage/v4/types"
+)
+
+const (
+	defaultArchiveConcurrency = 4
+	// archivePrefetchSize is the max bytes of every object prefetched into memory.
+	archivePrefetchSize = 4 * 1024 * 1024
+)
+
+// ArchiveFormat is the format of archives.
+type ArchiveFormat string
+
+const (
+	// ArchiveFormatTar is the tar format.
+	ArchiveFormatTar ArchiveFormat = "tar"
+	// ArchiveFormatZip is the zip format.
+	ArchiveFormatZip ArchiveFormat = "zip"
+)
+
+// ArchiveOptions is the options for Archive.
+type ArchiveOptions struct {
+	// Format is the format of the archive, default to ArchiveFormatTar.
+	Format ArchiveFormat
+	// Gzip will compress the tar archive with gzip, or compress entries of
+	// the zip archive with deflate.
+	Gzip bool
+	// Concurrency is the number of objects prefetched at the same time, default to 4.
+	Concurrency int
+}
+
+// Archive will write all objects under path into w as an archive, and return
+// the number of entries written.
+//
+// Entries are named by their path relative to the work dir, and dir marker
+// objects are written as dir entries. Up to Concurrency objects will be
+// fetched ahead while writing, with at most 4MiB of each buffered in memory.
+func (s *Storage) Archive(path string, w io.Writer, opt ArchiveOptions) (n int, err error) {
+	ctx := context.Background()
+	return s.ArchiveWithContext(ctx, path, w, opt)
+}
+
+// ArchiveWithContext will write all objects under path into w as an archive,
+// and return the number of entries written.
+func (s *Storage) ArchiveWithContext(ctx context.Context, path string, w io.Writer, opt ArchiveOptions) (n int, err error) {
+	defer func() {
+		err = s.formatError("archive", err, path)
+	}()
+
+	return s.archive(ctx, path, w, opt)
+}
+
+func (s *Storage) archive(ctx context.Context, path string, w io.Writer, opt ArchiveOptions) (n int, err error) {
+	aw, err := newArchiveWriter(w, opt)
+	if err != nil {
+		return 0, err
+	}
+	concurrency := defaultArchiveConcurrency
+	if opt.Concurrency > 0 {
+		concurrency = opt.Concurrency
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	it, err := s.list(ctx, path, pairStorageList{})
+	if err != nil {
+		return 0, err
+	}
+
+	// Entries are queued in the listing order, and sem bounds the number of
+	// entries being prefetched or waiting to be written.
+	queue := make(chan *archiveEntry, concurrency)
+	sem := make(chan struct{}, concurrency)
+	listErr := make(chan error, 1)
+	go func() {
+		defer close(queue)
+		listErr <- s.prefetchObjects(ctx, it, queue, sem)
+	}()
+	defer func() {
+		cancel()
+		// Close objects prefetched but not written.
+		for e := range queue {
+			<-e.ready
+			e.close()
+		}
+	}()
+
+	for e := range queue {
+		<-e.ready
+		err = e.err
+		if err == nil {
+			err = aw.WriteEntry(e.object, e.reader)
+		}
+		e.close()
+		<-sem
+		if err != nil {
+			return n, err
+		}
+		n++
+	}
+	err = <-listErr
+	if err != nil {
+		return n, err
+	}
+	return n, aw.Close()
+}
+
+// archiveEntry is an object being prefetched, reader is ready to be read
+// after ready is closed.
+type archiveEntry struct {
+	object *types.Object
+	ready  chan struct{}
+
+	reader io.Reader
+	closer io.Closer
+	err    error
+}
+
+func (e *archiveEntry) close() {
+	if e.closer != nil {
+		e.closer.Close()
+	}
+}
+
+func (s *Storage) prefetchObjects(ctx context.Context, it *types.ObjectIterator, queue chan<- *archiveEntry, sem chan struct{}) error {
+	for {
+		o, err := it.Next()
+		if err != nil {
+			if errors.Is(err, types.IterateDone) {
+				return nil
+			}
+			return err
+		}
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		e := &archiveEntry{
+			object: o,
+			ready:  make(chan struct{}),
+		}
+		go s.prefetchObject(ctx, e)
+		// queue never blocks because it has the same capacity as sem.
+		queue <- e
+	}
+}
+
+func (s *Storage) prefetchObject(ctx context.Context, e *archiveEntry) {
+	defer close(e.ready)
+
+	size := e.object.MustGetContentLength()
+	if e.object.Mode.IsDir() || size == 0 {
+		e.reader = bytes.NewReader(nil)
+		return
+	}
+
+	output, err := s.client.GetObject(ctx, s.bucket, e.object.ID, minio.GetObjectOptions{})
+	if err != nil {
+		e.err = err
+		return
+	}
+	e.closer = output
+
+	buf := make([]byte, archivePrefetchSize)
+	if size < archivePrefetchSize {
+		buf = buf[:size]
+	}
+	_, err = io.ReadFull(output, buf)
+	if err != nil {
+		e.err = err
+		return
+	}
+	e.reader = io.MultiReader(bytes.NewReader(buf), output)
+}
+
+// archiveWriter writes objects into an archive.
+type archiveWriter interface {
+	WriteEntry(o *types.Object, r io.Reader) error
+	Close() error
+}
+
+func newArchiveWriter(w io.Writer, opt ArchiveOptions) (archiveWriter, error) {
+	switch opt.Format {
+	case "", ArchiveFormatTar:
+		aw := &tarArchiveWriter{}
+		if opt.Gzip {
+			aw.gw = gzip.NewWriter(w)
+			w = aw.gw
+		}
+		aw.tw = tar.NewWriter(w)
+		return aw, nil
+	case ArchiveFormatZip:
+		aw := &zipArchiveWriter{
+			zw:     zip.NewWriter(w),
+			method: zip.Store,
+		}
+		if opt.Gzip {
+			aw.method = zip.Deflate
+		}
+		return aw, nil
+	default:
+		return nil, fmt.Errorf("archive format %q is not supported", opt.Format)
+	}
+}
+
+type tarArchiveWriter struct {
+	tw *tar.Writer
+	gw *gzip.Writer
+}
+
+func (aw *tarArchiveWriter) WriteEntry(o *types.Object, r io.Reader) error {
+	hdr := &tar.Header{
+		Name:    o.Path,
+		Mode:    0644,
+		ModTime: o.MustGetLastModified(),
+	}
+	if o.Mode.IsDir() {
+		hdr.Typeflag = tar.TypeDir
+		hdr.Name += "/"
+		hdr.Mode = 0755
+	} else {
+		hdr.Typeflag = tar.TypeReg
+		hdr.Size = o.MustGetContentLength()
+	}
+	err := aw.tw.WriteHeader(hdr)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(aw.tw, r)
+	return err
+}
+
+func (aw *tarArchiveWriter) Close() error {
+	err := aw.tw.Close()
+	if err != nil {
+		return err
+	}
+	if aw.gw != nil {
+		return aw.gw.Close()
+	}
+	return nil
+}
+
+type zipArchiveWriter struct {
+	zw     *zip.Writer
+	method uint16
+}
+
+func (aw *zipArchiveWriter) WriteEntry(o *types.Object, r io.Reader) error {
+	hdr := &zip.FileHeader{
+		Name:     o.Path,
+		Method:   aw.method,
+		Modified: o.MustGetLastModified(),
+	}
+	if o.Mode.IsDir() {
+		hdr.Name += "/"
+		hdr.Method = zip.Store
+	}
+	fw, err := aw.zw.CreateHeader(hdr)
+	if err != nil {
+		return err
+	}
+	if o.Mode.IsDir() {
+		return nil
+	}
+	_, err = io.Copy(fw, r)
+	return err
+}
+
+func (aw *zipArchiveWriter) Close() error {
+	return aw.zw.Close()
+}
@@ -0,0 +1,120 @@
+package minio
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestArchive(t *testing.T) {
+	f := newFakeServer(t)
+	expected := map[string]string{
+		"a":   "aaa",
+		"b/c": strings.Repeat("c", archivePrefetchSize+1),
+		"d/":  "",
+		"e":   "",
+	}
+	for k, v := range expected {
+		f.put("bucket", "w/"+k, v)
+	}
+	f.put("bucket", "x/a", "x")
+	store := f.newStorage(t, "bucket", "/w/")
+
+	for _, opt := range []ArchiveOptions{
+		{Format: ArchiveFormatTar},
+		{Format: ArchiveFormatTar, Gzip: true, Concurrency: 1},
+		{Format: ArchiveFormatZip},
+		{Format: ArchiveFormatZip, Gzip: true},
+	} {
+		t.Run(fmt.Sprintf("%s gzip %v", opt.Format, opt.Gzip), func(t *testing.T) {
+			var buf bytes.Buffer
+			n, err := store.Archive("", &buf, opt)
+			if err != nil {
+				t.Fatalf("archive: %v", err)
+			}
+			if n != len(expected) {
+				t.Errorf("archive wrote %d entries, expected %d", n, len(expected))
+			}
+
+			var actual map[string]string
+			if opt.Format == ArchiveFormatZip {
+				actual = readZipEntries(t, buf.Bytes())
+			} else {
+				actual = readTarEntries(t, &buf, opt.Gzip)
+			}
+			if !reflect.DeepEqual(actual, expected) {
+				t.Errorf("archive has %d entries, expected %d", len(actual), len(expected))
+				for k, v := range actual {
+					if expected[k] != v {
+						t.Errorf("entry %s has %d bytes, expected %d", k, len(v), len(expected[k]))
+					}
+				}
+			}
+		})
+	}
+
+	_, err := store.Archive("", io.Discard, ArchiveOptions{Format: "rar"})
+	if err == nil {
+		t.Errorf("archive with format rar should fail")
+	}
+}
+
+// readTarEntries returns the content of entries in the tar archive by name.
+func readTarEntries(t *testing.T, r io.Reader, gz bool) map[string]string {
+	t.Helper()
+	if gz {
+		gr, err := gzip.NewReader(r)
+		if err != nil {
+			t.Fatalf("read gzip: %v", err)
+		}
+		r = gr
+	}
+	entries := make(map[string]string)
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return entries
+		}
+		if err != nil {
+			t.Fatalf("read tar: %v", err)
+		}
+		content, err := io.ReadAll(tr)
+		if err != nil {
+			t.Fatalf("read tar: %v", err)
+		}
+		if strings.HasSuffix(hdr.Name, "/") != (hdr.Typeflag == tar.TypeDir) {
+			t.Errorf("entry %s has type %c", hdr.Name, hdr.Typeflag)
+		}
+		entries[hdr.Name] = string(content)
+	}
+}
+
+// readZipEntries returns the content of entries in the zip archive by name.
+func readZipEntries(t *testing.T, b []byte) map[string]string {
+	t.Helper()
+	zr, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		t.Fatalf("read zip: %v", err)
+	}
+	entries := make(map[string]string)
+	for _, v := range zr.File {
+		rc, err := v.Open()
+		if err != nil {
+			t.Fatalf("read zip: %v", err)
+		}
+		content, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatalf("read zip: %v", err)
+		}
+		entries[v.Name] = string(content)
+	}
+	return entries
+}
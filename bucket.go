@@ -0,0 +1,246 @@
+package minio
+
+import (
+	"context"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/sse"
+	"github.com/minio/minio-go/v7/pkg/tags"
+)
+
+const (
+	// EncryptionAlgorithmS3 encrypts objects with keys managed by the server.
+	EncryptionAlgorithmS3 = "AES256"
+	// EncryptionAlgorithmKMS encrypts objects with the key in KMS.
+	EncryptionAlgorithmKMS = "aws:kms"
+)
+
+// BucketEncryption is the default encryption configuration of a bucket.
+type BucketEncryption struct {
+	// Algorithm is EncryptionAlgorithmS3, EncryptionAlgorithmKMS or empty if
+	// default encryption is not configured.
+	Algorithm string
+	// KMSKeyID is the KMS key used by EncryptionAlgorithmKMS, default to the
+	// default key of the KMS.
+	KMSKeyID string
+}
+
+// ObjectLockConfig is the object lock configuration of a bucket.
+type ObjectLockConfig struct {
+	// Enabled means object lock is enabled, which could only be set while
+	// creating the bucket.
+	Enabled bool
+	// Mode is the default retention mode of new objects, `GOVERNANCE`,
+	// `COMPLIANCE` or empty if there is no default retention.
+	Mode string
+	// Validity is the default retention period of new objects in Unit.
+	Validity uint
+	// Unit is `DAYS` or `YEARS`.
+	Unit string
+}
+
+// BucketInfo is the facts of the bucket that Storage works on.
+type BucketInfo struct {
+	Location   string
+	Versioning VersioningConfig
+	Encryption BucketEncryption
+	ObjectLock ObjectLockConfig
+}
+
+// BucketInfo will get the location, versioning, default encryption and
+// object lock configuration of the bucket.
+//
+// Metadata doesn't send any request, and only returns the location given
+// while creating the Storage.
+func (s *Storage) BucketInfo() (info *BucketInfo, err error) {
+	ctx := context.Background()
+	return s.BucketInfoWithContext(ctx)
+}
+
+// BucketInfoWithContext will get the location, versioning, default encryption
+// and object lock configuration of the bucket.
+func (s *Storage) BucketInfoWithContext(ctx context.Context) (info *BucketInfo, err error) {
+	defer func() {
+		err = s.formatError("bucket_info", err)
+	}()
+
+	return s.bucketInfo(ctx)
+}
+
+func (s *Storage) bucketInfo(ctx context.Context) (info *BucketInfo, err error) {
+	info = &BucketInfo{}
+	info.Location, err = s.client.GetBucketLocation(ctx, s.bucket)
+	if err != nil {
+		return nil, err
+	}
+	info.Versioning, err = getVersioning(ctx, s.client, s.bucket)
+	if err != nil {
+		return nil, err
+	}
+	info.Encryption, err = getEncryption(ctx, s.client, s.bucket)
+	if err != nil {
+		return nil, err
+	}
+	info.ObjectLock, err = getObjectLock(ctx, s.client, s.bucket)
+	if err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// GetLocation will get the location of bucket name.
+func (s *Service) GetLocation(name string) (location string, err error) {
+	ctx := context.Background()
+	return s.GetLocationWithContext(ctx, name)
+}
+
+// GetLocationWithContext will get the location of bucket name.
+func (s *Service) GetLocationWithContext(ctx context.Context, name string) (location string, err error) {
+	defer func() {
+		err = s.formatError("get_location", err, name)
+	}()
+
+	return s.service.GetBucketLocation(ctx, name)
+}
+
+// GetEncryption will get the default encryption configuration of bucket name.
+func (s *Service) GetEncryption(name string) (enc BucketEncryption, err error) {
+	ctx := context.Background()
+	return s.GetEncryptionWithContext(ctx, name)
+}
+
+// GetEncryptionWithContext will get the default encryption configuration of bucket name.
+func (s *Service) GetEncryptionWithContext(ctx context.Context, name string) (enc BucketEncryption, err error) {
+	defer func() {
+		err = s.formatError("get_encryption", err, name)
+	}()
+
+	return getEncryption(ctx, s.service, name)
+}
+
+// SetEncryption will set the default encryption configuration of bucket name.
+//
+// Empty Algorithm will remove the default encryption configuration.
+func (s *Service) SetEncryption(name string, enc BucketEncryption) (err error) {
+	ctx := context.Background()
+	return s.SetEncryptionWithContext(ctx, name, enc)
+}
+
+// SetEncryptionWithContext will set the default encryption configuration of bucket name.
+func (s *Service) SetEncryptionWithContext(ctx context.Context, name string, enc BucketEncryption) (err error) {
+	defer func() {
+		err = s.formatError("set_encryption", err, name)
+	}()
+
+	return s.setEncryption(ctx, name, enc)
+}
+
+// GetTags will get the tags of bucket name.
+func (s *Service) GetTags(name string) (m map[string]string, err error) {
+	ctx := context.Background()
+	return s.GetTagsWithContext(ctx, name)
+}
+
+// GetTagsWithContext will get the tags of bucket name.
+func (s *Service) GetTagsWithContext(ctx context.Context, name string) (m map[string]string, err error) {
+	defer func() {
+		err = s.formatError("get_tags", err, name)
+	}()
+
+	return s.getTags(ctx, name)
+}
+
+// SetTags will replace the tags of bucket name with m.
+//
+// Empty m will remove all tags of the bucket.
+func (s *Service) SetTags(name string, m map[string]string) (err error) {
+	ctx := context.Background()
+	return s.SetTagsWithContext(ctx, name, m)
+}
+
+// SetTagsWithContext will replace the tags of bucket name with m.
+func (s *Service) SetTagsWithContext(ctx context.Context, name string, m map[string]string) (err error) {
+	defer func() {
+		err = s.formatError("set_tags", err, name)
+	}()
+
+	return s.setTags(ctx, name, m)
+}
+
+func getObjectLock(ctx context.Context, client *minio.Client, name string) (cfg ObjectLockConfig, err error) {
+	enabled, mode, validity, unit, err := client.GetObjectLockConfig(ctx, name)
+	if err != nil {
+		if minio.ToErrorResponse(err).Code == "ObjectLockConfigurationNotFoundError" {
+			return ObjectLockConfig{}, nil
+		}
+		return ObjectLockConfig{}, err
+	}
+
+	cfg.Enabled = enabled == "Enabled"
+	if mode != nil {
+		cfg.Mode = mode.String()
+	}
+	if validity != nil && unit != nil {
+		cfg.Validity = *validity
+		cfg.Unit = unit.String()
+	}
+	return cfg, nil
+}
+
+func getEncryption(ctx context.Context, client *minio.Client, name string) (enc BucketEncryption, err error) {
+	output, err := client.GetBucketEncryption(ctx, name)
+	if err != nil {
+		if minio.ToErrorResponse(err).Code == "ServerSideEncryptionConfigurationNotFoundError" {
+			return BucketEncryption{}, nil
+		}
+		return BucketEncryption{}, err
+	}
+	if len(output.Rules) == 0 {
+		return BucketEncryption{}, nil
+	}
+
+	enc.Algorithm = output.Rules[0].Apply.SSEAlgorithm
+	enc.KMSKeyID = output.Rules[0].Apply.KmsMasterKeyID
+	return enc, nil
+}
+
+func (s *Service) setEncryption(ctx context.Context, name string, enc BucketEncryption) (err error) {
+	if enc.Algorithm == "" {
+		return s.service.RemoveBucketEncryption(ctx, name)
+	}
+
+	cfg := &sse.Configuration{
+		Rules: []sse.Rule{
+			{
+				Apply: sse.ApplySSEByDefault{
+					SSEAlgorithm:   enc.Algorithm,
+					KmsMasterKeyID: enc.KMSKeyID,
+				},
+			},
+		},
+	}
+	return s.service.SetBucketEncryption(ctx, name, cfg)
+}
+
+func (s *Service) getTags(ctx context.Context, name string) (m map[string]string, err error) {
+	output, err := s.service.GetBucketTagging(ctx, name)
+	if err != nil {
+		if minio.ToErrorResponse(err).Code == "NoSuchTagSet" {
+			return map[string]string{}, nil
+		}
+		return nil, err
+	}
+	return output.ToMap(), nil
+}
+
+func (s *Service) setTags(ctx context.Context, name string, m map[string]string) (err error) {
+	if len(m) == 0 {
+		return s.service.RemoveBucketTagging(ctx, name)
+	}
+
+	t, err := tags.MapToBucketTags(m)
+	if err != nil {
+		return err
+	}
+	return s.service.SetBucketTagging(ctx, name, t)
+}
@@ -0,0 +1,86 @@
+package minio
+
+import (
+	"reflect"
+	"testing"
+
+	ps "github.com/beyondstorage/go-storage/v4/pairs"
+)
+
+func TestBucketInfo(t *testing.T) {
+	f := newFakeServer(t)
+	store := f.newStorage(t, "bucket", "/")
+
+	// Nothing is configured.
+	f.setConfig("bucket", "versioning", `<VersioningConfiguration></VersioningConfiguration>`)
+	info, err := store.BucketInfo()
+	if err != nil {
+		t.Fatalf("bucket info: %v", err)
+	}
+	expected := &BucketInfo{Location: "us-east-1"}
+	if !reflect.DeepEqual(info, expected) {
+		t.Errorf("bucket info is %+v, expected %+v", info, expected)
+	}
+
+	f.setConfig("bucket", "versioning", `<VersioningConfiguration><Status>Enabled</Status></VersioningConfiguration>`)
+	f.setConfig("bucket", "encryption", `<ServerSideEncryptionConfiguration><Rule><ApplyServerSideEncryptionByDefault>`+
+		`<SSEAlgorithm>aws:kms</SSEAlgorithm><KMSMasterKeyID>key</KMSMasterKeyID>`+
+		`</ApplyServerSideEncryptionByDefault></Rule></ServerSideEncryptionConfiguration>`)
+	f.setConfig("bucket", "object-lock", `<ObjectLockConfiguration><ObjectLockEnabled>Enabled</ObjectLockEnabled>`+
+		`<Rule><DefaultRetention><Mode>GOVERNANCE</Mode><Days>30</Days></DefaultRetention></Rule></ObjectLockConfiguration>`)
+	info, err = store.BucketInfo()
+	if err != nil {
+		t.Fatalf("bucket info: %v", err)
+	}
+	expected = &BucketInfo{
+		Location:   "us-east-1",
+		Versioning: VersioningConfig{Status: VersioningEnabled},
+		Encryption: BucketEncryption{Algorithm: EncryptionAlgorithmKMS, KMSKeyID: "key"},
+		ObjectLock: ObjectLockConfig{Enabled: true, Mode: "GOVERNANCE", Validity: 30, Unit: "DAYS"},
+	}
+	if !reflect.DeepEqual(info, expected) {
+		t.Errorf("bucket info is %+v, expected %+v", info, expected)
+	}
+}
+
+func TestMetadataWithoutRequest(t *testing.T) {
+	f := newFakeServer(t)
+	store := f.newStorage(t, "bucket", "/")
+
+	meta := store.Metadata()
+	if meta.Name != "bucket" {
+		t.Errorf("metadata has name %s", meta.Name)
+	}
+	if n := f.count("GetBucketLocation"); n != 0 {
+		t.Errorf("metadata sent %d requests", n)
+	}
+}
+
+func TestMetadataLocation(t *testing.T) {
+	f := newFakeServer(t)
+	store := f.newStorage(t, "bucket", "/")
+	srv := &Service{service: store.client}
+
+	if v, ok := store.Metadata().GetLocation(); ok {
+		t.Errorf("metadata has location %s, expected none", v)
+	}
+
+	st, err := srv.Create("new", ps.WithLocation("eu-west-1"))
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if v, _ := st.Metadata().GetLocation(); v != "eu-west-1" {
+		t.Errorf("metadata of the created bucket has location %s, expected eu-west-1", v)
+	}
+
+	st, err = srv.newStorage(ps.WithName("bucket"), ps.WithLocation("us-east-1"))
+	if err != nil {
+		t.Fatalf("new storage: %v", err)
+	}
+	if v, _ := st.Metadata().GetLocation(); v != "us-east-1" {
+		t.Errorf("metadata has location %s, expected us-east-1", v)
+	}
+	if n := f.count("GetBucketLocation"); n != 0 {
+		t.Errorf("metadata sent %d GetBucketLocation requests", n)
+	}
+}
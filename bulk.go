@@ -0,0 +1,155 @@
+package minio
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+
+	"github.com/beyondstorage/go-storage/v4/types"
+)
+
+// BulkEntry is a file to be written by BulkWrite.
+type BulkEntry struct {
+	Path string
+	// Reader must provide exactly Size bytes, it will not be closed by BulkWrite.
+	Reader io.Reader
+	// Size must not be negative, entries of unknown size will be failed.
+	Size int64
+	// ModTime is the last modified time of the object, default to the time
+	// the entry is added.
+	ModTime     time.Time
+	ContentType string
+}
+
+// BulkWriteOptions is the options for BulkWrite.
+type BulkWriteOptions struct {
+	// Compress will compress the archive with S2, which is supported by MinIO only.
+	Compress bool
+	// InMemory will build the archive in memory instead of a temporary file.
+	InMemory bool
+}
+
+// BulkWriteResult is the result of BulkWrite.
+type BulkWriteResult struct {
+	// Written is the number of entries written.
+	Written int
+	// Failed is the errors of entries failed to be read, keyed by path.
+	Failed map[string]error
+}
+
+// errBulkWriteStopped means the archive upload stopped before all entries are consumed.
+var errBulkWriteStopped = errors.New("bulk write stopped")
+
+// BulkWrite will write all entries returned by next as one archive which will be
+// extracted by the server, next must return IterateDone after the last entry.
+//
+// Entries are read one by one into memory, entries failed to be read will be
+// skipped and reported in the result. The archive is uploaded in one request,
+// so the total size must not exceed 5GiB.
+func (s *Storage) BulkWrite(next func() (*BulkEntry, error), opt BulkWriteOptions) (r *BulkWriteResult, err error) {
+	ctx := context.Background()
+	return s.BulkWriteWithContext(ctx, next, opt)
+}
+
+// BulkWriteWithContext will write all entries returned by next as one archive
+// which will be extracted by the server.
+func (s *Storage) BulkWriteWithContext(ctx context.Context, next func() (*BulkEntry, error), opt BulkWriteOptions) (r *BulkWriteResult, err error) {
+	defer func() {
+		err = s.formatError("bulk_write", err)
+	}()
+
+	return s.bulkWrite(ctx, next, opt)
+}
+
+func (s *Storage) bulkWrite(ctx context.Context, next func() (*BulkEntry, error), opt BulkWriteOptions) (r *BulkWriteResult, err error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	options := minio.SnowballOptions{
+		Compress: opt.Compress,
+		InMemory: opt.InMemory,
+	}
+	objChan := make(chan minio.SnowballObject)
+	done := make(chan struct{})
+	var uploadErr error
+	go func() {
+		defer close(done)
+		uploadErr = s.client.PutObjectsSnowball(ctx, s.bucket, options, objChan)
+	}()
+
+	r = &BulkWriteResult{
+		Failed: make(map[string]error),
+	}
+	err = s.sendBulkEntries(ctx, next, objChan, done, r)
+	if err != nil && !errors.Is(err, errBulkWriteStopped) {
+		// Stop the upload of the incomplete archive before closing objChan,
+		// otherwise it could be uploaded as a complete one.
+		cancel()
+		close(objChan)
+		<-done
+		return nil, err
+	}
+	close(objChan)
+
+	<-done
+	if uploadErr != nil {
+		return nil, uploadErr
+	}
+	return r, nil
+}
+
+func (s *Storage) sendBulkEntries(ctx context.Context, next func() (*BulkEntry, error), objChan chan<- minio.SnowballObject, done <-chan struct{}, r *BulkWriteResult) error {
+	for {
+		e, err := next()
+		if err != nil {
+			if errors.Is(err, types.IterateDone) {
+				return nil
+			}
+			return err
+		}
+
+		if e.Size < 0 {
+			r.Failed[e.Path] = fmt.Errorf("size %d is negative", e.Size)
+			continue
+		}
+		// Read the whole content before adding it to the archive, because
+		// an entry can't be dropped once its header has been written.
+		content := make([]byte, e.Size)
+		if e.Size > 0 {
+			if e.Reader == nil {
+				r.Failed[e.Path] = fmt.Errorf("reader is nil but size is not 0")
+				continue
+			}
+			_, err = io.ReadFull(e.Reader, content)
+			if err != nil {
+				r.Failed[e.Path] = err
+				continue
+			}
+		}
+
+		obj := minio.SnowballObject{
+			Key:     s.getAbsPath(e.Path),
+			Size:    e.Size,
+			ModTime: e.ModTime,
+			Content: bytes.NewReader(content),
+		}
+		if e.ContentType != "" {
+			obj.Headers = http.Header{"Content-Type": []string{e.ContentType}}
+		}
+
+		select {
+		case objChan <- obj:
+			r.Written++
+		case <-done:
+			return errBulkWriteStopped
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package minio
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/beyondstorage/go-storage/v4/types"
+)
+
+// bulkEntries returns a next func of BulkWrite which returns entries and then err.
+func bulkEntries(err error, entries ...*BulkEntry) func() (*BulkEntry, error) {
+	return func() (*BulkEntry, error) {
+		if len(entries) == 0 {
+			return nil, err
+		}
+		e := entries[0]
+		entries = entries[1:]
+		return e, nil
+	}
+}
+
+func TestBulkWrite(t *testing.T) {
+	f := newFakeServer(t)
+	store := f.newStorage(t, "bucket", "/w/")
+
+	next := bulkEntries(types.IterateDone,
+		&BulkEntry{Path: "a", Reader: strings.NewReader("aaa"), Size: 3},
+		&BulkEntry{Path: "b", Reader: strings.NewReader("b"), Size: 3},
+		&BulkEntry{Path: "c", Size: 0},
+		&BulkEntry{Path: "d", Reader: strings.NewReader("d"), Size: -1},
+	)
+	r, err := store.BulkWrite(next, BulkWriteOptions{InMemory: true})
+	if err != nil {
+		t.Fatalf("bulk write: %v", err)
+	}
+	if r.Written != 2 {
+		t.Errorf("bulk write wrote %d entries, expected 2", r.Written)
+	}
+	_, okB := r.Failed["b"]
+	_, okD := r.Failed["d"]
+	if !okB || !okD || len(r.Failed) != 2 {
+		t.Errorf("bulk write failed %v, expected b and d", r.Failed)
+	}
+	if n := f.count("PutObject"); n != 1 {
+		t.Errorf("bulk write sent %d PutObject requests, expected 1", n)
+	}
+}
+
+func TestBulkWriteNextError(t *testing.T) {
+	f := newFakeServer(t)
+	store := f.newStorage(t, "bucket", "/w/")
+
+	errNext := errors.New("next")
+	for _, inMemory := range []bool{true, false} {
+		next := bulkEntries(errNext, &BulkEntry{Path: "a", Reader: strings.NewReader("aaa"), Size: 3})
+		_, err := store.BulkWrite(next, BulkWriteOptions{InMemory: inMemory})
+		if err == nil || !strings.Contains(err.Error(), errNext.Error()) {
+			t.Errorf("bulk write returned %v, expected the error of next", err)
+		}
+	}
+	// The incomplete archive must not be uploaded.
+	if keys := f.keys("bucket"); len(keys) != 0 {
+		t.Errorf("bulk write uploaded %v, expected nothing", keys)
+	}
+}
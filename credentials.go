@@ -0,0 +1,247 @@
+package minio
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go/v7/pkg/credentials"
+
+	ps "github.com/beyondstorage/go-storage/v4/pairs"
+	"github.com/beyondstorage/go-storage/v4/pkg/credential"
+	"github.com/beyondstorage/go-storage/v4/services"
+)
+
+// CredentialProvider provides credentials to sign every request, it's the
+// same as credentials.Provider of minio-go.
+//
+// Credentials will be retrieved again once IsExpired returns true, so rotated
+// credentials will be used by new requests without recreating the Servicer
+// or Storager, while requests being sent keep their signatures.
+type CredentialProvider = credentials.Provider
+
+// CredentialRefreshFunc returns the current credentials and the time they
+// should be refreshed at.
+//
+// Zero expiration means the function will be called before every request,
+// so it must be cheap, like reading a value rotated by others in memory.
+type CredentialRefreshFunc func() (v credentials.Value, expiration time.Time, err error)
+
+// NewCredentialRefresher returns a CredentialProvider which gets credentials
+// via fn, it could be used via the credential_provider pair.
+func NewCredentialRefresher(fn CredentialRefreshFunc) CredentialProvider {
+	return &credentialRefresher{fn: fn}
+}
+
+type credentialRefresher struct {
+	credentials.Expiry
+
+	fn CredentialRefreshFunc
+}
+
+func (p *credentialRefresher) Retrieve() (credentials.Value, error) {
+	v, expiration, err := p.fn()
+	if err != nil {
+		return credentials.Value{}, err
+	}
+	p.SetExpiration(expiration, 0)
+	return v, nil
+}
+
+// IAMCredential will get temporary credentials from the IAM role of the EC2
+// instance, ECS task or EKS pod.
+type IAMCredential struct {
+	// Endpoint is the metadata endpoint, default to detect from the environment.
+	Endpoint string
+}
+
+// AssumeRoleCredential will get temporary credentials via STS AssumeRole
+// with long-lived keys.
+type AssumeRoleCredential struct {
+	// STSEndpoint is the STS endpoint, default to the service endpoint.
+	STSEndpoint string
+	AccessKey   string
+	SecretKey   string
+	// RoleARN and RoleSessionName are only required by AWS STS.
+	RoleARN         string
+	RoleSessionName string
+	// Location is the region of the STS endpoint.
+	Location string
+	// Duration is the lifetime of credentials, default to 1 hour.
+	Duration time.Duration
+}
+
+// WebIdentityCredential will get temporary credentials via STS
+// AssumeRoleWithWebIdentity with an OpenID Connect token.
+type WebIdentityCredential struct {
+	// STSEndpoint is the STS endpoint, default to the service endpoint.
+	STSEndpoint string
+	// TokenFile is the file of the token, which will be read every time
+	// credentials are refreshed, like the projected service account token
+	// in Kubernetes.
+	TokenFile string
+	RoleARN   string
+	// Duration is the lifetime of credentials, default to the token's.
+	Duration time.Duration
+}
+
+// LDAPIdentityCredential will get temporary credentials via STS
+// AssumeRoleWithLDAPIdentity with an LDAP account, which is a MinIO extension.
+type LDAPIdentityCredential struct {
+	// STSEndpoint is the STS endpoint, default to the service endpoint.
+	STSEndpoint string
+	Username    string
+	Password    string
+	// Duration is the lifetime of credentials, default to 1 hour.
+	Duration time.Duration
+}
+
+// newCredentials will chain credential providers given by pairs, credentials
+// will be got from the first provider that yields keys.
+//
+// The credential pair comes first, followed by assume_role_credential,
+// web_identity_credential, ldap_identity_credential and iam_credential.
+// The credential_provider pair can't be combined with others, so that failed
+// refreshes will be retried instead of falling back to other credentials.
+func newCredentials(opt pairServiceNew, endpoint string) (*credentials.Credentials, error) {
+	var providers []credentials.Provider
+
+	if opt.HasCredentialProvider {
+		if opt.HasCredential || opt.HasAssumeRoleCredential || opt.HasWebIdentityCredential ||
+			opt.HasLdapIdentityCredential || opt.HasIamCredential {
+			return nil, fmt.Errorf("credential_provider can't be combined with other credentials")
+		}
+		providers = append(providers, opt.CredentialProvider)
+	}
+	if opt.HasCredential {
+		cp, err := credential.Parse(opt.Credential)
+		if err != nil {
+			return nil, err
+		}
+		switch cp.Protocol() {
+		case credential.ProtocolHmac:
+			ak, sk := cp.Hmac()
+			providers = append(providers, &credentials.Static{
+				Value: credentials.Value{
+					AccessKeyID:     ak,
+					SecretAccessKey: sk,
+					SignerType:      credentials.SignatureV4,
+				},
+			})
+		case credential.ProtocolEnv:
+			providers = append(providers, &credentials.EnvMinio{}, &credentials.EnvAWS{})
+		case credential.ProtocolFile:
+			// MinIO client config is JSON, while AWS credentials file is INI.
+			if strings.HasSuffix(cp.File(), ".json") {
+				providers = append(providers, &credentials.FileMinioClient{Filename: cp.File()})
+			} else {
+				providers = append(providers, &credentials.FileAWSCredentials{Filename: cp.File()})
+			}
+		default:
+			return nil, services.PairUnsupportedError{Pair: ps.WithCredential(opt.Credential)}
+		}
+	}
+
+	client := &http.Client{Transport: http.DefaultTransport}
+	if opt.HasAssumeRoleCredential {
+		v := opt.AssumeRoleCredential
+		providers = append(providers, &credentials.STSAssumeRole{
+			Client:      client,
+			STSEndpoint: stsEndpoint(v.STSEndpoint, endpoint),
+			Options: credentials.STSAssumeRoleOptions{
+				AccessKey:       v.AccessKey,
+				SecretKey:       v.SecretKey,
+				RoleARN:         v.RoleARN,
+				RoleSessionName: v.RoleSessionName,
+				Location:        v.Location,
+				DurationSeconds: int(v.Duration.Seconds()),
+			},
+		})
+	}
+	if opt.HasWebIdentityCredential {
+		v := opt.WebIdentityCredential
+		providers = append(providers, &credentials.STSWebIdentity{
+			Client:      client,
+			STSEndpoint: stsEndpoint(v.STSEndpoint, endpoint),
+			RoleARN:     v.RoleARN,
+			GetWebIDTokenExpiry: func() (*credentials.WebIdentityToken, error) {
+				token, err := os.ReadFile(v.TokenFile)
+				if err != nil {
+					return nil, err
+				}
+				return &credentials.WebIdentityToken{
+					Token:  strings.TrimSpace(string(token)),
+					Expiry: int(v.Duration.Seconds()),
+				}, nil
+			},
+		})
+	}
+	if opt.HasLdapIdentityCredential {
+		v := opt.LdapIdentityCredential
+		providers = append(providers, &credentials.LDAPIdentity{
+			Client:          client,
+			STSEndpoint:     stsEndpoint(v.STSEndpoint, endpoint),
+			LDAPUsername:    v.Username,
+			LDAPPassword:    v.Password,
+			RequestedExpiry: v.Duration,
+		})
+	}
+	if opt.HasIamCredential {
+		providers = append(providers, &credentials.IAM{
+			Client:   client,
+			Endpoint: opt.IamCredential.Endpoint,
+		})
+	}
+
+	if len(providers) == 0 {
+		return nil, services.PairRequiredError{Keys: []string{"credential"}}
+	}
+	return credentials.New(&credentialChain{providers: providers}), nil
+}
+
+// credentialChain gets credentials from the first provider that yields keys.
+//
+// Unlike credentials.Chain of minio-go, an error will be returned if no
+// provider yields keys, instead of sending requests without signature.
+type credentialChain struct {
+	providers []credentials.Provider
+	current   credentials.Provider
+}
+
+func (c *credentialChain) Retrieve() (credentials.Value, error) {
+	c.current = nil
+
+	var errs []error
+	for _, p := range c.providers {
+		v, err := p.Retrieve()
+		if err == nil && v.AccessKeyID == "" {
+			err = fmt.Errorf("%T doesn't provide access key", p)
+		}
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		c.current = p
+		return v, nil
+	}
+	return credentials.Value{}, fmt.Errorf("no credentials are provided: %w", errors.Join(errs...))
+}
+
+// IsExpired returns true until credentials have been retrieved, so that
+// providers will be tried again by the next request.
+func (c *credentialChain) IsExpired() bool {
+	if c.current == nil {
+		return true
+	}
+	return c.current.IsExpired()
+}
+
+func stsEndpoint(v, endpoint string) string {
+	if v != "" {
+		return v
+	}
+	return endpoint
+}
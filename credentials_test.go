@@ -0,0 +1,203 @@
+package minio
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/minio/minio-go/v7/pkg/credentials"
+
+	ps "github.com/beyondstorage/go-storage/v4/pairs"
+)
+
+// signedRequest is a request received by the fake server with the access key
+// it's signed with.
+type signedRequest struct {
+	path      string
+	accessKey string
+}
+
+func TestCredentialRotation(t *testing.T) {
+	var mu sync.Mutex
+	var requests []signedRequest
+	slowStarted := make(chan struct{})
+	slowRelease := make(chan struct{})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth := r.Header.Get("Authorization")
+		ak := strings.TrimPrefix(auth, "AWS4-HMAC-SHA256 Credential=")
+		ak = ak[:strings.Index(ak, "/")]
+
+		mu.Lock()
+		requests = append(requests, signedRequest{path: r.URL.Path, accessKey: ak})
+		mu.Unlock()
+
+		if _, ok := r.URL.Query()["location"]; ok {
+			w.Write([]byte(`<LocationConstraint>us-east-1</LocationConstraint>`))
+			return
+		}
+		if r.URL.Path == "/bucket/slow" {
+			close(slowStarted)
+			<-slowRelease
+		}
+		w.Header().Set("Last-Modified", time.Now().UTC().Format(http.TimeFormat))
+		w.Header().Set("ETag", `"d41d8cd98f00b204e9800998ecf8427e"`)
+		w.Header().Set("Content-Length", "0")
+	}))
+	defer server.Close()
+
+	var ckMu sync.Mutex
+	accessKey := "old"
+	provider := NewCredentialRefresher(func() (credentials.Value, time.Time, error) {
+		ckMu.Lock()
+		defer ckMu.Unlock()
+		return credentials.Value{
+			AccessKeyID:     accessKey,
+			SecretAccessKey: accessKey + "-secret",
+			SignerType:      credentials.SignatureV4,
+		}, time.Time{}, nil
+	})
+
+	store, err := NewStorager(
+		WithCredentialProvider(provider),
+		ps.WithEndpoint("http:"+strings.TrimPrefix(server.URL, "http://")),
+		ps.WithName("bucket"),
+	)
+	if err != nil {
+		t.Fatalf("new storager: %v", err)
+	}
+
+	_, err = store.Stat("before")
+	if err != nil {
+		t.Fatalf("stat before rotation: %v", err)
+	}
+
+	// Start a request with the old credentials and keep it in flight.
+	slowErr := make(chan error, 1)
+	go func() {
+		_, err := store.Stat("slow")
+		slowErr <- err
+	}()
+	<-slowStarted
+
+	ckMu.Lock()
+	accessKey = "new"
+	ckMu.Unlock()
+	mu.Lock()
+	rotatedAt := len(requests)
+	mu.Unlock()
+
+	for _, path := range []string{"after-1", "after-2"} {
+		_, err = store.Stat(path)
+		if err != nil {
+			t.Fatalf("stat %s after rotation: %v", path, err)
+		}
+	}
+
+	close(slowRelease)
+	err = <-slowErr
+	if err != nil {
+		t.Errorf("in-flight stat during rotation: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	for i, v := range requests {
+		switch {
+		case v.path == "/bucket/slow" && v.accessKey != "old":
+			t.Errorf("in-flight request %s is signed with %q, expected the old key", v.path, v.accessKey)
+		case i >= rotatedAt && v.accessKey != "new":
+			t.Errorf("request %s after rotation is signed with stale key %q", v.path, v.accessKey)
+		}
+	}
+	if len(requests) <= rotatedAt {
+		t.Errorf("no request is sent after rotation")
+	}
+}
+
+func TestCredentialEnvMissing(t *testing.T) {
+	for _, k := range []string{
+		"MINIO_ROOT_USER", "MINIO_ROOT_PASSWORD", "MINIO_ACCESS_KEY", "MINIO_SECRET_KEY",
+		"AWS_ACCESS_KEY_ID", "AWS_ACCESS_KEY", "AWS_SECRET_ACCESS_KEY", "AWS_SECRET_KEY",
+	} {
+		t.Setenv(k, "")
+	}
+	f := newFakeServer(t)
+	f.put("bucket", "a", "a")
+	_, store, err := newServicerAndStorager(
+		ps.WithCredential("env:"),
+		ps.WithEndpoint("http:"+strings.TrimPrefix(f.URL, "http://")),
+		ps.WithName("bucket"),
+	)
+	if err != nil {
+		t.Fatalf("new storager: %v", err)
+	}
+
+	// Requests must not be sent without signature.
+	_, err = store.Stat("a")
+	if err == nil {
+		t.Errorf("stat without credentials should fail")
+	}
+	if n := f.count("HeadObject"); n != 0 {
+		t.Errorf("stat sent %d requests without credentials, expected 0", n)
+	}
+
+	// Credentials will be retrieved again by the next request.
+	t.Setenv("MINIO_ACCESS_KEY", "ak")
+	t.Setenv("MINIO_SECRET_KEY", "sk")
+	_, err = store.Stat("a")
+	if err != nil {
+		t.Errorf("stat with credentials: %v", err)
+	}
+}
+
+func TestCredentialRefreshFailure(t *testing.T) {
+	f := newFakeServer(t)
+	f.put("bucket", "a", "a")
+	endpoint := ps.WithEndpoint("http:" + strings.TrimPrefix(f.URL, "http://"))
+
+	var calls int
+	provider := NewCredentialRefresher(func() (credentials.Value, time.Time, error) {
+		calls++
+		if calls == 1 {
+			return credentials.Value{}, time.Time{}, errors.New("refresh failed")
+		}
+		return credentials.Value{
+			AccessKeyID:     "ak",
+			SecretAccessKey: "sk",
+			SignerType:      credentials.SignatureV4,
+		}, time.Now().Add(time.Hour), nil
+	})
+	_, store, err := newServicerAndStorager(WithCredentialProvider(provider), endpoint, ps.WithName("bucket"))
+	if err != nil {
+		t.Fatalf("new storager: %v", err)
+	}
+
+	// The failed refresh should fail the request, and be retried by the next one.
+	_, err = store.Stat("a")
+	if err == nil {
+		t.Errorf("stat with the failed refresh should fail")
+	}
+	if n := f.count("HeadObject"); n != 0 {
+		t.Errorf("stat sent %d requests with the failed refresh, expected 0", n)
+	}
+	for i := 0; i < 2; i++ {
+		_, err = store.Stat("a")
+		if err != nil {
+			t.Errorf("stat after the failed refresh: %v", err)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("credentials are refreshed %d times, expected 2", calls)
+	}
+
+	// Other credentials could not be used as the fallback of credential_provider.
+	_, _, err = newServicerAndStorager(WithCredentialProvider(provider), ps.WithCredential("hmac:ak:sk"), endpoint, ps.WithName("bucket"))
+	if err == nil {
+		t.Errorf("credential_provider combined with credential should fail")
+	}
+}
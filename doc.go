@@ -1,5 +1,11 @@
 /*
 Package minio provided support for minio by go-storage.
+
+List sends every ListObjectsV2 request via minio.Core, which doesn't accept
+a context. Canceling the context stops the iteration and returns the error of
+the context at once, but the request in flight can't be aborted and will run
+in background until the server responds. A listing stopped this way could be
+resumed by the continuation token of the last page.
 */
 package minio
 
@@ -0,0 +1,281 @@
+package minio
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	defaultExtractParallelism = 4
+	// extractBufferSize is the max size of tar entries buffered in memory to
+	// be written in parallel, larger entries are written one by one.
+	extractBufferSize = 8 * 1024 * 1024
+	// extractMtimeMetadata is the user metadata key of the modification time
+	// in the archive, the value is unix seconds with fraction, the same as rclone.
+	extractMtimeMetadata = "mtime"
+)
+
+// ExtractOptions is the options for Extract.
+type ExtractOptions struct {
+	// Format is the format of the archive, default to ArchiveFormatTar.
+	Format ArchiveFormat
+	// Gzip means the tar archive is compressed with gzip.
+	Gzip bool
+	// Parallelism is the number of entries written at the same time, default to 4.
+	Parallelism int
+}
+
+// ExtractResult is the result of Extract.
+type ExtractResult struct {
+	// Written is the number of entries written.
+	Written int
+	// Failed is the errors of entries failed to be written, keyed by entry name.
+	Failed map[string]error
+}
+
+// Extract will write every regular file in the archive read from r as an
+// object under path.
+//
+// The modification time of entries will be kept in user metadata `mtime`,
+// and the content type is detected by the extension or the content.
+// Entries failed to be written are reported in the result, while errors of
+// reading the archive will stop the extraction.
+//
+// Zip archives need random access, r will be read via io.ReaderAt if it's
+// an *os.File or has a Size method like *bytes.Reader, otherwise the whole
+// archive will be read into memory.
+func (s *Storage) Extract(path string, r io.Reader, opt ExtractOptions) (res *ExtractResult, err error) {
+	ctx := context.Background()
+	return s.ExtractWithContext(ctx, path, r, opt)
+}
+
+// ExtractWithContext will write every regular file in the archive read from r
+// as an object under path.
+func (s *Storage) ExtractWithContext(ctx context.Context, path string, r io.Reader, opt ExtractOptions) (res *ExtractResult, err error) {
+	defer func() {
+		err = s.formatError("extract", err, path)
+	}()
+
+	return s.extract(ctx, path, r, opt)
+}
+
+func (s *Storage) extract(ctx context.Context, path string, r io.Reader, opt ExtractOptions) (res *ExtractResult, err error) {
+	parallelism := defaultExtractParallelism
+	if opt.Parallelism > 0 {
+		parallelism = opt.Parallelism
+	}
+
+	x := &extractor{
+		ctx:     ctx,
+		storage: s,
+		dst:     path,
+		result: ExtractResult{
+			Failed: make(map[string]error),
+		},
+	}
+	x.group.SetLimit(parallelism)
+
+	switch opt.Format {
+	case "", ArchiveFormatTar:
+		err = x.extractTar(r, opt.Gzip)
+	case ArchiveFormatZip:
+		err = x.extractZip(r)
+	default:
+		err = fmt.Errorf("archive format %q is not supported", opt.Format)
+	}
+	// Wait for started writes even if the archive is broken.
+	x.group.Wait()
+	if err != nil {
+		return nil, err
+	}
+	if err = ctx.Err(); err != nil {
+		return nil, err
+	}
+	return &x.result, nil
+}
+
+// extractor writes entries of an archive in parallel.
+type extractor struct {
+	ctx     context.Context
+	storage *Storage
+	dst     string
+	group   errgroup.Group
+
+	mu     sync.Mutex
+	result ExtractResult
+}
+
+func (x *extractor) extractTar(r io.Reader, gz bool) error {
+	if gz {
+		gr, err := gzip.NewReader(r)
+		if err != nil {
+			return err
+		}
+		defer gr.Close()
+		r = gr
+	}
+
+	tr := tar.NewReader(r)
+	for {
+		// Stop reading the archive once canceled, writes will fail anyway.
+		if err := x.ctx.Err(); err != nil {
+			return err
+		}
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if !hdr.FileInfo().Mode().IsRegular() {
+			continue
+		}
+
+		// Small entries are buffered and written in parallel, while large
+		// entries have to be written before reading the next one.
+		if hdr.Size > extractBufferSize {
+			x.writeEntry(hdr.Name, tr, hdr.Size, hdr.ModTime)
+			continue
+		}
+		content := make([]byte, hdr.Size)
+		_, err = io.ReadFull(tr, content)
+		if err != nil {
+			return err
+		}
+		name, modTime := hdr.Name, hdr.ModTime
+		x.group.Go(func() error {
+			x.writeEntry(name, bytes.NewReader(content), int64(len(content)), modTime)
+			return nil
+		})
+	}
+}
+
+func (x *extractor) extractZip(r io.Reader) error {
+	ra, size, err := newZipReaderAt(r)
+	if err != nil {
+		return err
+	}
+	zr, err := zip.NewReader(ra, size)
+	if err != nil {
+		return err
+	}
+
+	for _, f := range zr.File {
+		if err := x.ctx.Err(); err != nil {
+			return err
+		}
+		if !f.Mode().IsRegular() {
+			continue
+		}
+		f := f
+		x.group.Go(func() error {
+			rc, err := f.Open()
+			if err != nil {
+				x.fail(f.Name, err)
+				return nil
+			}
+			defer rc.Close()
+			x.writeEntry(f.Name, rc, int64(f.UncompressedSize64), f.Modified)
+			return nil
+		})
+	}
+	return nil
+}
+
+// writeEntry writes an entry via write and records the result.
+func (x *extractor) writeEntry(name string, r io.Reader, size int64, modTime time.Time) {
+	rel, err := extractEntryPath(name)
+	if err != nil {
+		x.fail(name, err)
+		return
+	}
+
+	opt := pairStorageWrite{
+		HasContentType: true,
+		ContentType:    mime.TypeByExtension(path.Ext(rel)),
+	}
+	if opt.ContentType == "" {
+		br := bufio.NewReaderSize(r, 512)
+		// Peek returns less data without error at EOF.
+		head, _ := br.Peek(512)
+		opt.ContentType = http.DetectContentType(head)
+		r = br
+	}
+	if !modTime.IsZero() {
+		opt.HasUserMetadata = true
+		opt.UserMetadata = map[string]string{
+			extractMtimeMetadata: strconv.FormatFloat(float64(modTime.UnixNano())/1e9, 'f', -1, 64),
+		}
+	}
+
+	_, err = x.storage.write(x.ctx, path.Join(x.dst, rel), r, size, opt)
+	if err != nil {
+		x.fail(name, err)
+		return
+	}
+
+	x.mu.Lock()
+	x.result.Written++
+	x.mu.Unlock()
+}
+
+func (x *extractor) fail(name string, err error) {
+	x.mu.Lock()
+	x.result.Failed[name] = err
+	x.mu.Unlock()
+}
+
+// extractEntryPath returns the path of the entry relative to the destination,
+// entries escaping the destination via `..` will be rejected.
+func extractEntryPath(name string) (string, error) {
+	for _, v := range strings.Split(name, "/") {
+		if v == ".." {
+			return "", fmt.Errorf("entry %q is outside of the archive", name)
+		}
+	}
+	rel := strings.TrimPrefix(path.Clean("/"+name), "/")
+	if rel == "" {
+		return "", fmt.Errorf("entry %q doesn't have a name", name)
+	}
+	return rel, nil
+}
+
+// newZipReaderAt returns r as io.ReaderAt with its size for zip.NewReader.
+func newZipReaderAt(r io.Reader) (io.ReaderAt, int64, error) {
+	switch v := r.(type) {
+	case *os.File:
+		fi, err := v.Stat()
+		if err != nil {
+			return nil, 0, err
+		}
+		return v, fi.Size(), nil
+	case interface {
+		io.ReaderAt
+		Size() int64
+	}:
+		return v, v.Size(), nil
+	}
+
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, 0, err
+	}
+	return bytes.NewReader(content), int64(len(content)), nil
+}
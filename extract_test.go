@@ -0,0 +1,165 @@
+package minio
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"context"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// extractEntry is an entry written into archives by extract tests.
+type extractEntry struct {
+	name    string
+	content string
+	dir     bool
+}
+
+func newTarArchive(t *testing.T, entries []extractEntry, modTime time.Time) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, v := range entries {
+		hdr := &tar.Header{Name: v.name, Mode: 0644, Size: int64(len(v.content)), ModTime: modTime, Typeflag: tar.TypeReg}
+		if v.dir {
+			hdr.Typeflag, hdr.Size = tar.TypeDir, 0
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatalf("write tar: %v", err)
+		}
+		if _, err := tw.Write([]byte(v.content)); err != nil {
+			t.Fatalf("write tar: %v", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("write tar: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func newZipArchive(t *testing.T, entries []extractEntry, modTime time.Time) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, v := range entries {
+		w, err := zw.CreateHeader(&zip.FileHeader{Name: v.name, Method: zip.Deflate, Modified: modTime})
+		if err != nil {
+			t.Fatalf("write zip: %v", err)
+		}
+		if _, err = w.Write([]byte(v.content)); err != nil {
+			t.Fatalf("write zip: %v", err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("write zip: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestExtract(t *testing.T) {
+	modTime := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+	entries := []extractEntry{
+		{name: "d/", dir: true},
+		{name: "d/a.txt", content: "aaa"},
+		{name: "./b", content: "<html><body>b</body></html>"},
+		{name: "/abs/c", content: "c"},
+		{name: "../escape", content: "x"},
+		{name: "d/../../escape", content: "x"},
+	}
+
+	for _, format := range []ArchiveFormat{ArchiveFormatTar, ArchiveFormatZip} {
+		t.Run(string(format), func(t *testing.T) {
+			f := newFakeServer(t)
+			store := f.newStorage(t, "bucket", "/w/")
+
+			var archive []byte
+			if format == ArchiveFormatZip {
+				archive = newZipArchive(t, entries, modTime)
+			} else {
+				archive = newTarArchive(t, entries, modTime)
+			}
+			res, err := store.Extract("dst", bytes.NewReader(archive), ExtractOptions{Format: format, Parallelism: 2})
+			if err != nil {
+				t.Fatalf("extract: %v", err)
+			}
+			if res.Written != 3 {
+				t.Errorf("extract wrote %d entries, expected 3", res.Written)
+			}
+			if len(res.Failed) != 2 || res.Failed["../escape"] == nil || res.Failed["d/../../escape"] == nil {
+				t.Errorf("extract failed %v, expected entries escaping the destination", res.Failed)
+			}
+
+			expected := []string{"w/dst/abs/c", "w/dst/b", "w/dst/d/a.txt"}
+			if keys := f.keys("bucket"); !reflect.DeepEqual(keys, expected) {
+				t.Fatalf("extract wrote %v, expected %v", keys, expected)
+			}
+			for key, contentType := range map[string]string{
+				"w/dst/d/a.txt": "text/plain; charset=utf-8",
+				"w/dst/b":       "text/html; charset=utf-8",
+			} {
+				o, _ := f.get("bucket", key)
+				if o.contentType != contentType {
+					t.Errorf("%s has content type %q, expected %q", key, o.contentType, contentType)
+				}
+				mtime := strconv.FormatInt(modTime.Unix(), 10)
+				if v := o.userMetadata["Mtime"]; v != mtime {
+					t.Errorf("%s has mtime %q, expected %q", key, v, mtime)
+				}
+			}
+		})
+	}
+}
+
+func TestExtractCanceled(t *testing.T) {
+	f := newFakeServer(t)
+	store := f.newStorage(t, "bucket", "/w/")
+	archive := newTarArchive(t, []extractEntry{{name: "a", content: "a"}}, time.Now())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := store.ExtractWithContext(ctx, "", bytes.NewReader(archive), ExtractOptions{})
+	if err == nil || !strings.Contains(err.Error(), context.Canceled.Error()) {
+		t.Errorf("extract returned %v, expected context.Canceled", err)
+	}
+	if n := f.count("PutObject"); n != 0 {
+		t.Errorf("extract sent %d PutObject requests, expected 0", n)
+	}
+}
+
+func TestExtractEntryPath(t *testing.T) {
+	for name, expected := range map[string]string{
+		"a":         "a",
+		"a/b":       "a/b",
+		"./a/./b":   "a/b",
+		"a//b":      "a/b",
+		"/a/b":      "a/b",
+		"//a":       "a",
+		"a/b/":      "a/b",
+		"..":        "",
+		"../a":      "",
+		"a/../../b": "",
+		"a/../b":    "",
+		"/../etc/x": "",
+		"a/..":      "",
+		"":          "",
+		"/":         "",
+		".":         "",
+		"..a/b..":   "..a/b..",
+		"a/.../b":   "a/.../b",
+	} {
+		rel, err := extractEntryPath(name)
+		if expected == "" {
+			if err == nil {
+				t.Errorf("entry %q returned %q, expected an error", name, rel)
+			}
+			continue
+		}
+		if err != nil || rel != expected {
+			t.Errorf("entry %q returned %q, %v, expected %q", name, rel, err, expected)
+		}
+	}
+}
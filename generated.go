@@ -28,7 +28,21 @@ const Type = "minio"
 
 // ObjectSystemMetadata stores system metadata for object.
 type ObjectSystemMetadata struct {
-	StorageClass string
+	ChecksumCrc32        string
+	ChecksumCrc32c       string
+	ChecksumSha1         string
+	ChecksumSha256       string
+	Expiration           time.Time
+	ExpirationRuleID     string
+	OwnerID              string
+	OwnerName            string
+	ReplicationStatus    string
+	RestoreExpiryTime    time.Time
+	RestoreOngoing       bool
+	ServerSideEncryption string
+	StorageClass         string
+	TagCount             int
+	VersionID            string
 }
 
 // GetObjectSystemMetadata will get ObjectSystemMetadata from Object.
@@ -52,7 +66,21 @@ func setObjectSystemMetadata(o *Object, sm ObjectSystemMetadata) {
 
 // StorageSystemMetadata stores system metadata for object.
 type StorageSystemMetadata struct {
-	StorageClass string
+	ChecksumCrc32        string
+	ChecksumCrc32c       string
+	ChecksumSha1         string
+	ChecksumSha256       string
+	Expiration           time.Time
+	ExpirationRuleID     string
+	OwnerID              string
+	OwnerName            string
+	ReplicationStatus    string
+	RestoreExpiryTime    time.Time
+	RestoreOngoing       bool
+	ServerSideEncryption string
+	StorageClass         string
+	TagCount             int
+	VersionID            string
 }
 
 // GetStorageSystemMetadata will get StorageSystemMetadata from Storage.
@@ -74,6 +102,28 @@ func setStorageSystemMetadata(s *StorageMeta, sm StorageSystemMetadata) {
 	s.SetSystemMetadata(sm)
 }
 
+// WithAssumeRoleCredential will apply assume_role_credential value to Options.
+//
+// specify the STS AssumeRole credential provider
+func WithAssumeRoleCredential(v AssumeRoleCredential) Pair {
+	return Pair{Key: "assume_role_credential", Value: v}
+}
+
+// WithBucketTags will apply bucket_tags value to Options.
+//
+// specify the tags of the bucket to create
+func WithBucketTags(v map[string]string) Pair {
+	return Pair{Key: "bucket_tags", Value: v}
+}
+
+// WithCredentialProvider will apply credential_provider value to Options.
+//
+// specify the provider of credentials which could be rotated without recreating the service, it
+// can't be combined with other credentials
+func WithCredentialProvider(v CredentialProvider) Pair {
+	return Pair{Key: "credential_provider", Value: v}
+}
+
 // WithDefaultServicePairs will apply default_service_pairs value to Options.
 func WithDefaultServicePairs(v DefaultServicePairs) Pair {
 	return Pair{Key: "default_service_pairs", Value: v}
@@ -99,11 +149,138 @@ func WithEnableVirtualDir() Pair {
 	return Pair{Key: "enable_virtual_dir", Value: true}
 }
 
+// WithEncryption will apply encryption value to Options.
+//
+// specify the default encryption configuration of the bucket to create
+func WithEncryption(v BucketEncryption) Pair {
+	return Pair{Key: "encryption", Value: v}
+}
+
+// WithGlob will apply glob value to Options.
+//
+// specify the glob pattern that the relative path of listed objects must match, dirs will not be filtered
+func WithGlob(v string) Pair {
+	return Pair{Key: "glob", Value: v}
+}
+
+// WithIamCredential will apply iam_credential value to Options.
+//
+// specify the IAM credential provider for EC2 instances, ECS tasks and EKS pods
+func WithIamCredential(v IAMCredential) Pair {
+	return Pair{Key: "iam_credential", Value: v}
+}
+
+// WithIfMatch will apply if_match value to Options.
+//
+// specify the etag that the object must match, otherwise the request will fail with ErrPreconditionFailed
+func WithIfMatch(v string) Pair {
+	return Pair{Key: "if_match", Value: v}
+}
+
+// WithIfModifiedSince will apply if_modified_since value to Options.
+//
+// specify the time after which the object must have been modified, otherwise the request will fail
+// with ErrPreconditionFailed
+func WithIfModifiedSince(v time.Time) Pair {
+	return Pair{Key: "if_modified_since", Value: v}
+}
+
+// WithIfNoneMatch will apply if_none_match value to Options.
+//
+// specify the etag that the object must not match, otherwise the request will fail with ErrPreconditionFailed
+//
+// Use `*` in write to create the object only if it doesn't exist.
+func WithIfNoneMatch(v string) Pair {
+	return Pair{Key: "if_none_match", Value: v}
+}
+
+// WithIfUnmodifiedSince will apply if_unmodified_since value to Options.
+//
+// specify the time after which the object must not have been modified, otherwise the request will
+// fail with ErrPreconditionFailed
+func WithIfUnmodifiedSince(v time.Time) Pair {
+	return Pair{Key: "if_unmodified_since", Value: v}
+}
+
+// WithLdapIdentityCredential will apply ldap_identity_credential value to Options.
+//
+// specify the STS AssumeRoleWithLDAPIdentity credential provider
+func WithLdapIdentityCredential(v LDAPIdentityCredential) Pair {
+	return Pair{Key: "ldap_identity_credential", Value: v}
+}
+
+// WithListMetadata will apply list_metadata value to Options.
+//
+// specify whether to return content type and user metadata of objects in list
+func WithListMetadata() Pair {
+	return Pair{Key: "list_metadata", Value: true}
+}
+
+// WithMaxSize will apply max_size value to Options.
+//
+// specify the max content length of listed objects, dirs will not be filtered
+func WithMaxSize(v int64) Pair {
+	return Pair{Key: "max_size", Value: v}
+}
+
+// WithMinSize will apply min_size value to Options.
+//
+// specify the min content length of listed objects, dirs will not be filtered
+func WithMinSize(v int64) Pair {
+	return Pair{Key: "min_size", Value: v}
+}
+
+// WithModifiedAfter will apply modified_after value to Options.
+//
+// specify the time after which listed objects must have been modified, dirs will not be filtered
+func WithModifiedAfter(v time.Time) Pair {
+	return Pair{Key: "modified_after", Value: v}
+}
+
+// WithModifiedBefore will apply modified_before value to Options.
+//
+// specify the time before which listed objects must have been modified, dirs will not be filtered
+func WithModifiedBefore(v time.Time) Pair {
+	return Pair{Key: "modified_before", Value: v}
+}
+
+// WithPageSize will apply page_size value to Options.
+//
+// specify the max number of objects returned per page in list, default to 100, larger values will be
+// reduced to 1000
+func WithPageSize(v int) Pair {
+	return Pair{Key: "page_size", Value: v}
+}
+
+// WithPublicURL will apply public_url value to Options.
+//
+// specify whether to reach the object via an unsigned url, the object must be readable by anonymous
+// users and expire is not allowed
+func WithPublicURL() Pair {
+	return Pair{Key: "public_url", Value: true}
+}
+
+// WithRegexp will apply regexp value to Options.
+//
+// specify the regular expression that the relative path of listed objects must match, dirs will not
+// be filtered
+func WithRegexp(v string) Pair {
+	return Pair{Key: "regexp", Value: v}
+}
+
 // WithServiceFeatures will apply service_features value to Options.
 func WithServiceFeatures(v ServiceFeatures) Pair {
 	return Pair{Key: "service_features", Value: v}
 }
 
+// WithStartAfter will apply start_after value to Options.
+//
+// specify the path after which the list starts, continuation_token will take precedence if both
+// set
+func WithStartAfter(v string) Pair {
+	return Pair{Key: "start_after", Value: v}
+}
+
 // WithStorageClass will apply storage_class value to Options.
 func WithStorageClass(v string) Pair {
 	return Pair{Key: "storage_class", Value: v}
@@ -114,7 +291,28 @@ func WithStorageFeatures(v StorageFeatures) Pair {
 	return Pair{Key: "storage_features", Value: v}
 }
 
-var pairMap = map[string]string{"content_md5": "string", "content_type": "string", "context": "context.Context", "continuation_token": "string", "credential": "string", "default_content_type": "string", "default_io_callback": "func([]byte)", "default_service_pairs": "DefaultServicePairs", "default_storage_pairs": "DefaultStoragePairs", "enable_virtual_dir": "bool", "endpoint": "string", "expire": "time.Duration", "http_client_options": "*httpclient.Options", "interceptor": "Interceptor", "io_callback": "func([]byte)", "list_mode": "ListMode", "location": "string", "multipart_id": "string", "name": "string", "object_mode": "ObjectMode", "offset": "int64", "service_features": "ServiceFeatures", "size": "int64", "storage_class": "string", "storage_features": "StorageFeatures", "work_dir": "string"}
+// WithUserMetadata will apply user_metadata value to Options.
+//
+// specify the user metadata of the object to write
+func WithUserMetadata(v map[string]string) Pair {
+	return Pair{Key: "user_metadata", Value: v}
+}
+
+// WithVersioning will apply versioning value to Options.
+//
+// specify the versioning configuration of the bucket to create
+func WithVersioning(v VersioningConfig) Pair {
+	return Pair{Key: "versioning", Value: v}
+}
+
+// WithWebIdentityCredential will apply web_identity_credential value to Options.
+//
+// specify the STS AssumeRoleWithWebIdentity credential provider
+func WithWebIdentityCredential(v WebIdentityCredential) Pair {
+	return Pair{Key: "web_identity_credential", Value: v}
+}
+
+var pairMap = map[string]string{"assume_role_credential": "AssumeRoleCredential", "bucket_tags": "map[string]string", "content_md5": "string", "content_type": "string", "context": "context.Context", "continuation_token": "string", "credential": "string", "credential_provider": "CredentialProvider", "default_content_type": "string", "default_io_callback": "func([]byte)", "default_service_pairs": "DefaultServicePairs", "default_storage_pairs": "DefaultStoragePairs", "enable_virtual_dir": "bool", "encryption": "BucketEncryption", "endpoint": "string", "expire": "time.Duration", "glob": "string", "http_client_options": "*httpclient.Options", "iam_credential": "IAMCredential", "if_match": "string", "if_modified_since": "time.Time", "if_none_match": "string", "if_unmodified_since": "time.Time", "interceptor": "Interceptor", "io_callback": "func([]byte)", "ldap_identity_credential": "LDAPIdentityCredential", "list_metadata": "bool", "list_mode": "ListMode", "location": "string", "max_size": "int64", "min_size": "int64", "modified_after": "time.Time", "modified_before": "time.Time", "multipart_id": "string", "name": "string", "object_mode": "ObjectMode", "offset": "int64", "page_size": "int", "public_url": "bool", "regexp": "string", "service_features": "ServiceFeatures", "size": "int64", "start_after": "string", "storage_class": "string", "storage_features": "StorageFeatures", "user_metadata": "map[string]string", "versioning": "VersioningConfig", "web_identity_credential": "WebIdentityCredential", "work_dir": "string"}
 var _ Servicer = &Service{}
 
 type ServiceFeatures struct {
@@ -125,15 +323,25 @@ type pairServiceNew struct {
 	pairs []Pair
 
 	// Required pairs
-	HasCredential bool
-	Credential    string
-	HasEndpoint   bool
-	Endpoint      string
+	HasEndpoint bool
+	Endpoint    string
 	// Optional pairs
-	HasDefaultServicePairs bool
-	DefaultServicePairs    DefaultServicePairs
-	HasServiceFeatures     bool
-	ServiceFeatures        ServiceFeatures
+	HasAssumeRoleCredential   bool
+	AssumeRoleCredential      AssumeRoleCredential
+	HasCredential             bool
+	Credential                string
+	HasCredentialProvider     bool
+	CredentialProvider        CredentialProvider
+	HasDefaultServicePairs    bool
+	DefaultServicePairs       DefaultServicePairs
+	HasIamCredential          bool
+	IamCredential             IAMCredential
+	HasLdapIdentityCredential bool
+	LdapIdentityCredential    LDAPIdentityCredential
+	HasServiceFeatures        bool
+	ServiceFeatures           ServiceFeatures
+	HasWebIdentityCredential  bool
+	WebIdentityCredential     WebIdentityCredential
 	// Enable features
 }
 
@@ -144,39 +352,66 @@ func parsePairServiceNew(opts []Pair) (pairServiceNew, error) {
 
 	for _, v := range opts {
 		switch v.Key {
+		case "endpoint":
+			if result.HasEndpoint {
+				continue
+			}
+			result.HasEndpoint = true
+			result.Endpoint = v.Value.(string)
+		case "assume_role_credential":
+			if result.HasAssumeRoleCredential {
+				continue
+			}
+			result.HasAssumeRoleCredential = true
+			result.AssumeRoleCredential = v.Value.(AssumeRoleCredential)
 		case "credential":
 			if result.HasCredential {
 				continue
 			}
 			result.HasCredential = true
 			result.Credential = v.Value.(string)
-		case "endpoint":
-			if result.HasEndpoint {
+		case "credential_provider":
+			if result.HasCredentialProvider {
 				continue
 			}
-			result.HasEndpoint = true
-			result.Endpoint = v.Value.(string)
+			result.HasCredentialProvider = true
+			result.CredentialProvider = v.Value.(CredentialProvider)
 		case "default_service_pairs":
 			if result.HasDefaultServicePairs {
 				continue
 			}
 			result.HasDefaultServicePairs = true
 			result.DefaultServicePairs = v.Value.(DefaultServicePairs)
+		case "iam_credential":
+			if result.HasIamCredential {
+				continue
+			}
+			result.HasIamCredential = true
+			result.IamCredential = v.Value.(IAMCredential)
+		case "ldap_identity_credential":
+			if result.HasLdapIdentityCredential {
+				continue
+			}
+			result.HasLdapIdentityCredential = true
+			result.LdapIdentityCredential = v.Value.(LDAPIdentityCredential)
 		case "service_features":
 			if result.HasServiceFeatures {
 				continue
 			}
 			result.HasServiceFeatures = true
 			result.ServiceFeatures = v.Value.(ServiceFeatures)
+		case "web_identity_credential":
+			if result.HasWebIdentityCredential {
+				continue
+			}
+			result.HasWebIdentityCredential = true
+			result.WebIdentityCredential = v.Value.(WebIdentityCredential)
 		}
 	}
 	// Enable features
 
 	// Default pairs
 
-	if !result.HasCredential {
-		return pairServiceNew{}, services.PairRequiredError{Keys: []string{"credential"}}
-	}
 	if !result.HasEndpoint {
 		return pairServiceNew{}, services.PairRequiredError{Keys: []string{"endpoint"}}
 	}
@@ -194,6 +429,14 @@ type pairServiceCreate struct {
 	pairs []Pair
 	// Required pairs
 	// Optional pairs
+	HasBucketTags bool
+	BucketTags    map[string]string
+	HasEncryption bool
+	Encryption    BucketEncryption
+	HasLocation   bool
+	Location      string
+	HasVersioning bool
+	Versioning    VersioningConfig
 }
 
 func (s *Service) parsePairServiceCreate(opts []Pair) (pairServiceCreate, error) {
@@ -202,6 +445,30 @@ func (s *Service) parsePairServiceCreate(opts []Pair) (pairServiceCreate, error)
 
 	for _, v := range opts {
 		switch v.Key {
+		case "bucket_tags":
+			if result.HasBucketTags {
+				continue
+			}
+			result.HasBucketTags = true
+			result.BucketTags = v.Value.(map[string]string)
+		case "encryption":
+			if result.HasEncryption {
+				continue
+			}
+			result.HasEncryption = true
+			result.Encryption = v.Value.(BucketEncryption)
+		case "location":
+			if result.HasLocation {
+				continue
+			}
+			result.HasLocation = true
+			result.Location = v.Value.(string)
+		case "versioning":
+			if result.HasVersioning {
+				continue
+			}
+			result.HasVersioning = true
+			result.Versioning = v.Value.(VersioningConfig)
 		default:
 			return pairServiceCreate{}, services.PairUnsupportedError{Pair: v}
 		}
@@ -378,6 +645,8 @@ type pairStorageNew struct {
 	DefaultIoCallback      func([]byte)
 	HasDefaultStoragePairs bool
 	DefaultStoragePairs    DefaultStoragePairs
+	HasLocation            bool
+	Location               string
 	HasStorageFeatures     bool
 	StorageFeatures        StorageFeatures
 	HasWorkDir             bool
@@ -418,6 +687,12 @@ func parsePairStorageNew(opts []Pair) (pairStorageNew, error) {
 			}
 			result.HasDefaultStoragePairs = true
 			result.DefaultStoragePairs = v.Value.(DefaultStoragePairs)
+		case "location":
+			if result.HasLocation {
+				continue
+			}
+			result.HasLocation = true
+			result.Location = v.Value.(string)
 		case "storage_features":
 			if result.HasStorageFeatures {
 				continue
@@ -475,6 +750,14 @@ type pairStorageCopy struct {
 	pairs []Pair
 	// Required pairs
 	// Optional pairs
+	HasIfMatch           bool
+	IfMatch              string
+	HasIfModifiedSince   bool
+	IfModifiedSince      time.Time
+	HasIfNoneMatch       bool
+	IfNoneMatch          string
+	HasIfUnmodifiedSince bool
+	IfUnmodifiedSince    time.Time
 }
 
 func (s *Storage) parsePairStorageCopy(opts []Pair) (pairStorageCopy, error) {
@@ -483,6 +766,30 @@ func (s *Storage) parsePairStorageCopy(opts []Pair) (pairStorageCopy, error) {
 
 	for _, v := range opts {
 		switch v.Key {
+		case "if_match":
+			if result.HasIfMatch {
+				continue
+			}
+			result.HasIfMatch = true
+			result.IfMatch = v.Value.(string)
+		case "if_modified_since":
+			if result.HasIfModifiedSince {
+				continue
+			}
+			result.HasIfModifiedSince = true
+			result.IfModifiedSince = v.Value.(time.Time)
+		case "if_none_match":
+			if result.HasIfNoneMatch {
+				continue
+			}
+			result.HasIfNoneMatch = true
+			result.IfNoneMatch = v.Value.(string)
+		case "if_unmodified_since":
+			if result.HasIfUnmodifiedSince {
+				continue
+			}
+			result.HasIfUnmodifiedSince = true
+			result.IfUnmodifiedSince = v.Value.(time.Time)
 		default:
 			return pairStorageCopy{}, services.PairUnsupportedError{Pair: v}
 		}
@@ -551,8 +858,28 @@ type pairStorageList struct {
 	pairs []Pair
 	// Required pairs
 	// Optional pairs
-	HasListMode bool
-	ListMode    ListMode
+	HasContinuationToken bool
+	ContinuationToken    string
+	HasGlob              bool
+	Glob                 string
+	HasListMetadata      bool
+	ListMetadata         bool
+	HasListMode          bool
+	ListMode             ListMode
+	HasMaxSize           bool
+	MaxSize              int64
+	HasMinSize           bool
+	MinSize              int64
+	HasModifiedAfter     bool
+	ModifiedAfter        time.Time
+	HasModifiedBefore    bool
+	ModifiedBefore       time.Time
+	HasPageSize          bool
+	PageSize             int
+	HasRegexp            bool
+	Regexp               string
+	HasStartAfter        bool
+	StartAfter           string
 }
 
 func (s *Storage) parsePairStorageList(opts []Pair) (pairStorageList, error) {
@@ -561,12 +888,72 @@ func (s *Storage) parsePairStorageList(opts []Pair) (pairStorageList, error) {
 
 	for _, v := range opts {
 		switch v.Key {
+		case "continuation_token":
+			if result.HasContinuationToken {
+				continue
+			}
+			result.HasContinuationToken = true
+			result.ContinuationToken = v.Value.(string)
+		case "glob":
+			if result.HasGlob {
+				continue
+			}
+			result.HasGlob = true
+			result.Glob = v.Value.(string)
+		case "list_metadata":
+			if result.HasListMetadata {
+				continue
+			}
+			result.HasListMetadata = true
+			result.ListMetadata = v.Value.(bool)
 		case "list_mode":
 			if result.HasListMode {
 				continue
 			}
 			result.HasListMode = true
 			result.ListMode = v.Value.(ListMode)
+		case "max_size":
+			if result.HasMaxSize {
+				continue
+			}
+			result.HasMaxSize = true
+			result.MaxSize = v.Value.(int64)
+		case "min_size":
+			if result.HasMinSize {
+				continue
+			}
+			result.HasMinSize = true
+			result.MinSize = v.Value.(int64)
+		case "modified_after":
+			if result.HasModifiedAfter {
+				continue
+			}
+			result.HasModifiedAfter = true
+			result.ModifiedAfter = v.Value.(time.Time)
+		case "modified_before":
+			if result.HasModifiedBefore {
+				continue
+			}
+			result.HasModifiedBefore = true
+			result.ModifiedBefore = v.Value.(time.Time)
+		case "page_size":
+			if result.HasPageSize {
+				continue
+			}
+			result.HasPageSize = true
+			result.PageSize = v.Value.(int)
+		case "regexp":
+			if result.HasRegexp {
+				continue
+			}
+			result.HasRegexp = true
+			result.Regexp = v.Value.(string)
+		case "start_after":
+			if result.HasStartAfter {
+				continue
+			}
+			result.HasStartAfter = true
+			result.StartAfter = v.Value.(string)
 		default:
 			return pairStorageList{}, services.PairUnsupportedError{Pair: v}
 		}
@@ -599,8 +986,10 @@ type pairStorageReach struct {
 	pairs []Pair
 	// Required pairs
 	// Optional pairs
-	HasExpire bool
-	Expire    time.Duration
+	HasExpire    bool
+	Expire       time.Duration
+	HasPublicURL bool
+	PublicURL    bool
 }
 
 func (s *Storage) parsePairStorageReach(opts []Pair) (pairStorageReach, error) {
@@ -615,6 +1004,12 @@ func (s *Storage) parsePairStorageReach(opts []Pair) (pairStorageReach, error) {
 			}
 			result.HasExpire = true
 			result.Expire = v.Value.(time.Duration)
+		case "public_url":
+			if result.HasPublicURL {
+				continue
+			}
+			result.HasPublicURL = true
+			result.PublicURL = v.Value.(bool)
 		default:
 			return pairStorageReach{}, services.PairUnsupportedError{Pair: v}
 		}
@@ -627,12 +1022,20 @@ type pairStorageRead struct {
 	pairs []Pair
 	// Required pairs
 	// Optional pairs
-	HasIoCallback bool
-	IoCallback    func([]byte)
-	HasOffset     bool
-	Offset        int64
-	HasSize       bool
-	Size          int64
+	HasIfMatch           bool
+	IfMatch              string
+	HasIfModifiedSince   bool
+	IfModifiedSince      time.Time
+	HasIfNoneMatch       bool
+	IfNoneMatch          string
+	HasIfUnmodifiedSince bool
+	IfUnmodifiedSince    time.Time
+	HasIoCallback        bool
+	IoCallback           func([]byte)
+	HasOffset            bool
+	Offset               int64
+	HasSize              bool
+	Size                 int64
 }
 
 func (s *Storage) parsePairStorageRead(opts []Pair) (pairStorageRead, error) {
@@ -641,6 +1044,30 @@ func (s *Storage) parsePairStorageRead(opts []Pair) (pairStorageRead, error) {
 
 	for _, v := range opts {
 		switch v.Key {
+		case "if_match":
+			if result.HasIfMatch {
+				continue
+			}
+			result.HasIfMatch = true
+			result.IfMatch = v.Value.(string)
+		case "if_modified_since":
+			if result.HasIfModifiedSince {
+				continue
+			}
+			result.HasIfModifiedSince = true
+			result.IfModifiedSince = v.Value.(time.Time)
+		case "if_none_match":
+			if result.HasIfNoneMatch {
+				continue
+			}
+			result.HasIfNoneMatch = true
+			result.IfNoneMatch = v.Value.(string)
+		case "if_unmodified_since":
+			if result.HasIfUnmodifiedSince {
+				continue
+			}
+			result.HasIfUnmodifiedSince = true
+			result.IfUnmodifiedSince = v.Value.(time.Time)
 		case "io_callback":
 			if result.HasIoCallback {
 				continue
@@ -671,8 +1098,16 @@ type pairStorageStat struct {
 	pairs []Pair
 	// Required pairs
 	// Optional pairs
-	HasObjectMode bool
-	ObjectMode    ObjectMode
+	HasIfMatch           bool
+	IfMatch              string
+	HasIfModifiedSince   bool
+	IfModifiedSince      time.Time
+	HasIfNoneMatch       bool
+	IfNoneMatch          string
+	HasIfUnmodifiedSince bool
+	IfUnmodifiedSince    time.Time
+	HasObjectMode        bool
+	ObjectMode           ObjectMode
 }
 
 func (s *Storage) parsePairStorageStat(opts []Pair) (pairStorageStat, error) {
@@ -681,6 +1116,30 @@ func (s *Storage) parsePairStorageStat(opts []Pair) (pairStorageStat, error) {
 
 	for _, v := range opts {
 		switch v.Key {
+		case "if_match":
+			if result.HasIfMatch {
+				continue
+			}
+			result.HasIfMatch = true
+			result.IfMatch = v.Value.(string)
+		case "if_modified_since":
+			if result.HasIfModifiedSince {
+				continue
+			}
+			result.HasIfModifiedSince = true
+			result.IfModifiedSince = v.Value.(time.Time)
+		case "if_none_match":
+			if result.HasIfNoneMatch {
+				continue
+			}
+			result.HasIfNoneMatch = true
+			result.IfNoneMatch = v.Value.(string)
+		case "if_unmodified_since":
+			if result.HasIfUnmodifiedSince {
+				continue
+			}
+			result.HasIfUnmodifiedSince = true
+			result.IfUnmodifiedSince = v.Value.(time.Time)
 		case "object_mode":
 			if result.HasObjectMode {
 				continue
@@ -703,10 +1162,16 @@ type pairStorageWrite struct {
 	ContentMd5      string
 	HasContentType  bool
 	ContentType     string
+	HasIfMatch      bool
+	IfMatch         string
+	HasIfNoneMatch  bool
+	IfNoneMatch     string
 	HasIoCallback   bool
 	IoCallback      func([]byte)
 	HasStorageClass bool
 	StorageClass    string
+	HasUserMetadata bool
+	UserMetadata    map[string]string
 }
 
 func (s *Storage) parsePairStorageWrite(opts []Pair) (pairStorageWrite, error) {
@@ -727,6 +1192,18 @@ func (s *Storage) parsePairStorageWrite(opts []Pair) (pairStorageWrite, error) {
 			}
 			result.HasContentType = true
 			result.ContentType = v.Value.(string)
+		case "if_match":
+			if result.HasIfMatch {
+				continue
+			}
+			result.HasIfMatch = true
+			result.IfMatch = v.Value.(string)
+		case "if_none_match":
+			if result.HasIfNoneMatch {
+				continue
+			}
+			result.HasIfNoneMatch = true
+			result.IfNoneMatch = v.Value.(string)
 		case "io_callback":
 			if result.HasIoCallback {
 				continue
@@ -739,6 +1216,12 @@ func (s *Storage) parsePairStorageWrite(opts []Pair) (pairStorageWrite, error) {
 			}
 			result.HasStorageClass = true
 			result.StorageClass = v.Value.(string)
+		case "user_metadata":
+			if result.HasUserMetadata {
+				continue
+			}
+			result.HasUserMetadata = true
+			result.UserMetadata = v.Value.(map[string]string)
 		default:
 			return pairStorageWrite{}, services.PairUnsupportedError{Pair: v}
 		}
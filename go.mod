@@ -1,11 +1,44 @@
 module github.com/beyondstorage/go-service-minio
 
-go 1.15
+go 1.21
 
 require (
 	github.com/beyondstorage/go-endpoint v1.1.0
 	github.com/beyondstorage/go-integration-test/v4 v4.6.0
 	github.com/beyondstorage/go-storage/v4 v4.8.0
-	github.com/google/uuid v1.3.0
-	github.com/minio/minio-go/v7 v7.0.14
+	github.com/google/uuid v1.6.0
+	github.com/minio/minio-go/v7 v7.0.73
+	golang.org/x/sync v0.8.0
+)
+
+require (
+	github.com/Xuanwo/gg v0.2.0 // indirect
+	github.com/Xuanwo/go-bufferpool v0.2.0 // indirect
+	github.com/Xuanwo/templateutils v0.1.0 // indirect
+	github.com/cpuguy83/go-md2man/v2 v2.0.0-20190314233015-f79a8a8ca69d // indirect
+	github.com/dave/dst v0.26.2 // indirect
+	github.com/dustin/go-humanize v1.0.1 // indirect
+	github.com/go-ini/ini v1.67.0 // indirect
+	github.com/goccy/go-json v0.10.3 // indirect
+	github.com/gopherjs/gopherjs v0.0.0-20181017120253-0766667cb4d1 // indirect
+	github.com/jtolds/gls v4.20.0+incompatible // indirect
+	github.com/kevinburke/go-bindata v3.22.0+incompatible // indirect
+	github.com/klauspost/compress v1.17.9 // indirect
+	github.com/klauspost/cpuid/v2 v2.2.8 // indirect
+	github.com/minio/md5-simd v1.1.2 // indirect
+	github.com/pelletier/go-toml v1.9.4 // indirect
+	github.com/rs/xid v1.5.0 // indirect
+	github.com/russross/blackfriday/v2 v2.0.1 // indirect
+	github.com/shurcooL/sanitized_anchor_name v1.0.0 // indirect
+	github.com/sirupsen/logrus v1.9.2 // indirect
+	github.com/smartystreets/assertions v0.0.0-20180927180507-b2de0cb4f26d // indirect
+	github.com/smartystreets/goconvey v1.6.4 // indirect
+	github.com/urfave/cli/v2 v2.3.0 // indirect
+	golang.org/x/crypto v0.26.0 // indirect
+	golang.org/x/mod v0.20.0 // indirect
+	golang.org/x/net v0.28.0 // indirect
+	golang.org/x/sys v0.23.0 // indirect
+	golang.org/x/text v0.17.0 // indirect
+	golang.org/x/tools v0.24.1 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
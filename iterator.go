@@ -1,7 +1,7 @@
 package minio
 
 import (
-	"strconv"
+	"net/url"
 
 	"github.com/minio/minio-go/v7"
 )
@@ -15,13 +15,47 @@ func (i *storagePageStatus) ContinuationToken() string {
 }
 
 type objectPageStatus struct {
-	bufferSize int
-	counter    int
-	options    minio.ListObjectsOptions
+	maxKeys   int
+	prefix    string
+	delimiter string
+	filter    objectFilter
 
-	objChan <-chan minio.ObjectInfo
+	withMetadata bool
+	// startAfter is only used by the first request, later requests will
+	// continue with the continuation token returned by the previous one.
+	startAfter        string
+	continuationToken string
+	// lastKey is the last key of the latest fetched page, or the start_after
+	// key before the first page. It's empty if the listing is resumed via
+	// continuation token.
+	lastKey string
 }
 
+// ContinuationToken returns the continuation token returned by the latest
+// ListObjectsV2 request.
+//
+// Passing it via continuation_token will resume the listing from the next page.
 func (i *objectPageStatus) ContinuationToken() string {
-	return strconv.Itoa(i.counter)
+	return i.continuationToken
+}
+
+type partPageStatus struct {
+	maxUploads int
+	prefix     string
+
+	keyMarker      string
+	uploadIDMarker string
+}
+
+// ContinuationToken returns the key and upload id markers of the next page.
+//
+// Passing it via continuation_token will resume the listing from the next page.
+func (i *partPageStatus) ContinuationToken() string {
+	if i.keyMarker == "" && i.uploadIDMarker == "" {
+		return ""
+	}
+	return url.Values{
+		partKeyMarker:      []string{i.keyMarker},
+		partUploadIDMarker: []string{i.uploadIDMarker},
+	}.Encode()
 }
@@ -0,0 +1,288 @@
+package minio
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/lifecycle"
+)
+
+// LifecycleRule is a lifecycle rule for objects under Path of the Storage.
+//
+// Tag and object size filters, expiration of delete markers and the number of
+// noncurrent versions to keep are not supported. Rules in the bucket using them
+// are not returned by GetLifecycle and are kept as is by SetLifecycle.
+type LifecycleRule struct {
+	// ID is the unique identifier of the rule in the bucket.
+	ID string
+	// Path is the path relative to the work dir that the rule applies to.
+	Path string
+	// Disabled will keep the rule in the bucket without applying it.
+	Disabled bool
+
+	// ExpirationDays will expire objects the given days after creation.
+	ExpirationDays int
+	// ExpirationDate will expire objects at the given date.
+	ExpirationDate time.Time
+	// NoncurrentExpirationDays will expire noncurrent versions the given days
+	// after they become noncurrent.
+	NoncurrentExpirationDays int
+
+	// TransitionDays will transition objects to TransitionStorageClass the given
+	// days after creation.
+	TransitionDays int
+	// TransitionDate will transition objects to TransitionStorageClass at the given date.
+	TransitionDate time.Time
+	// TransitionStorageClass is the storage class or remote tier to transition to.
+	TransitionStorageClass string
+	// NoncurrentTransitionDays will transition noncurrent versions to
+	// NoncurrentTransitionStorageClass the given days after they become noncurrent.
+	NoncurrentTransitionDays int
+	// NoncurrentTransitionStorageClass is the storage class or remote tier to
+	// transition noncurrent versions to.
+	NoncurrentTransitionStorageClass string
+
+	// AbortIncompleteUploadDays will abort incomplete multipart uploads the given
+	// days after initiation.
+	AbortIncompleteUploadDays int
+}
+
+// LifecycleDiff is the difference between the desired lifecycle rules and the
+// rules of the Storage in the bucket.
+type LifecycleDiff struct {
+	// Added are the desired rules that don't exist in the bucket.
+	Added []LifecycleRule
+	// Removed are the rules in the bucket that are not desired.
+	Removed []LifecycleRule
+	// Changed are the desired rules that exist in the bucket with different content.
+	Changed []LifecycleRule
+}
+
+// Empty returns true if the desired rules are the same as the rules in the bucket.
+func (d LifecycleDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// GetLifecycle will get lifecycle rules under the work dir.
+func (s *Storage) GetLifecycle() (rules []LifecycleRule, err error) {
+	ctx := context.Background()
+	return s.GetLifecycleWithContext(ctx)
+}
+
+// GetLifecycleWithContext will get lifecycle rules under the work dir.
+func (s *Storage) GetLifecycleWithContext(ctx context.Context) (rules []LifecycleRule, err error) {
+	defer func() {
+		err = s.formatError("get_lifecycle", err)
+	}()
+
+	return s.getLifecycle(ctx)
+}
+
+// SetLifecycle will replace lifecycle rules under the work dir with rules.
+//
+// Rules for other paths in the bucket will be kept as is, and passing no
+// rules will remove all rules under the work dir.
+func (s *Storage) SetLifecycle(rules []LifecycleRule) (err error) {
+	ctx := context.Background()
+	return s.SetLifecycleWithContext(ctx, rules)
+}
+
+// SetLifecycleWithContext will replace lifecycle rules under the work dir with rules.
+func (s *Storage) SetLifecycleWithContext(ctx context.Context, rules []LifecycleRule) (err error) {
+	defer func() {
+		err = s.formatError("set_lifecycle", err)
+	}()
+
+	return s.setLifecycle(ctx, rules)
+}
+
+// DiffLifecycle will compare rules with lifecycle rules under the work dir by ID.
+func (s *Storage) DiffLifecycle(rules []LifecycleRule) (diff LifecycleDiff, err error) {
+	ctx := context.Background()
+	return s.DiffLifecycleWithContext(ctx, rules)
+}
+
+// DiffLifecycleWithContext will compare rules with lifecycle rules under the work dir by ID.
+func (s *Storage) DiffLifecycleWithContext(ctx context.Context, rules []LifecycleRule) (diff LifecycleDiff, err error) {
+	defer func() {
+		err = s.formatError("diff_lifecycle", err)
+	}()
+
+	return s.diffLifecycle(ctx, rules)
+}
+
+func (s *Storage) getLifecycle(ctx context.Context) (rules []LifecycleRule, err error) {
+	cfg, err := s.getBucketLifecycle(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range cfg.Rules {
+		if !s.isLifecycleRuleOwned(v) {
+			continue
+		}
+		rules = append(rules, s.formatLifecycleRule(v))
+	}
+	return rules, nil
+}
+
+func (s *Storage) setLifecycle(ctx context.Context, rules []LifecycleRule) (err error) {
+	current, err := s.getBucketLifecycle(ctx)
+	if err != nil {
+		return err
+	}
+
+	cfg := lifecycle.NewConfiguration()
+	for _, v := range current.Rules {
+		if s.isLifecycleRuleOwned(v) {
+			continue
+		}
+		cfg.Rules = append(cfg.Rules, v)
+	}
+	for _, v := range rules {
+		for _, kept := range cfg.Rules {
+			if kept.ID == v.ID {
+				return fmt.Errorf("lifecycle rule %s conflicts with a rule which is not supported or not under the work dir", v.ID)
+			}
+		}
+		cfg.Rules = append(cfg.Rules, s.newLifecycleRule(v))
+	}
+	// Lifecycle configuration will be removed if there are no rules.
+	return s.client.SetBucketLifecycle(ctx, s.bucket, cfg)
+}
+
+func (s *Storage) diffLifecycle(ctx context.Context, rules []LifecycleRule) (diff LifecycleDiff, err error) {
+	current, err := s.getLifecycle(ctx)
+	if err != nil {
+		return LifecycleDiff{}, err
+	}
+
+	currentRules := make(map[string]LifecycleRule, len(current))
+	for _, v := range current {
+		currentRules[v.ID] = v
+	}
+	for _, v := range rules {
+		cv, ok := currentRules[v.ID]
+		if !ok {
+			diff.Added = append(diff.Added, v)
+			continue
+		}
+		delete(currentRules, v.ID)
+		if !reflect.DeepEqual(normalizeLifecycleRule(cv), normalizeLifecycleRule(v)) {
+			diff.Changed = append(diff.Changed, v)
+		}
+	}
+	// Keep the same order as the bucket.
+	for _, v := range current {
+		if _, ok := currentRules[v.ID]; ok {
+			diff.Removed = append(diff.Removed, v)
+		}
+	}
+	return diff, nil
+}
+
+// getBucketLifecycle returns an empty configuration if the bucket doesn't have one.
+func (s *Storage) getBucketLifecycle(ctx context.Context) (*lifecycle.Configuration, error) {
+	cfg, err := s.client.GetBucketLifecycle(ctx, s.bucket)
+	if err != nil {
+		if minio.ToErrorResponse(err).Code == "NoSuchLifecycleConfiguration" {
+			return lifecycle.NewConfiguration(), nil
+		}
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// isLifecycleRuleOwned checks whether the rule applies to a path under the work
+// dir, and could be represented by LifecycleRule without losing anything.
+func (s *Storage) isLifecycleRuleOwned(v lifecycle.Rule) bool {
+	return strings.HasPrefix(lifecycleRulePrefix(v), s.getAbsPath("")) && isLifecycleRuleSupported(v)
+}
+
+// isLifecycleRuleSupported checks whether the rule only uses fields supported
+// by LifecycleRule.
+func isLifecycleRuleSupported(v lifecycle.Rule) bool {
+	f := v.RuleFilter
+	if !f.Tag.IsEmpty() || f.ObjectSizeLessThan != 0 || f.ObjectSizeGreaterThan != 0 {
+		return false
+	}
+	// And is only supported with the prefix alone.
+	if len(f.And.Tags) != 0 || f.And.ObjectSizeLessThan != 0 || f.And.ObjectSizeGreaterThan != 0 {
+		return false
+	}
+	if bool(v.Expiration.DeleteMarker) || bool(v.Expiration.DeleteAll) || v.DelMarkerExpiration.Days != 0 {
+		return false
+	}
+	if v.NoncurrentVersionExpiration.NewerNoncurrentVersions != 0 || v.NoncurrentVersionTransition.NewerNoncurrentVersions != 0 {
+		return false
+	}
+	return true
+}
+
+func (s *Storage) newLifecycleRule(v LifecycleRule) lifecycle.Rule {
+	rule := lifecycle.Rule{
+		ID:     v.ID,
+		Status: "Enabled",
+		RuleFilter: lifecycle.Filter{
+			Prefix: s.getAbsPath(v.Path),
+		},
+	}
+	if v.Disabled {
+		rule.Status = "Disabled"
+	}
+
+	rule.Expiration.Days = lifecycle.ExpirationDays(v.ExpirationDays)
+	rule.Expiration.Date = lifecycle.ExpirationDate{Time: v.ExpirationDate}
+	rule.NoncurrentVersionExpiration.NoncurrentDays = lifecycle.ExpirationDays(v.NoncurrentExpirationDays)
+
+	rule.Transition.Days = lifecycle.ExpirationDays(v.TransitionDays)
+	rule.Transition.Date = lifecycle.ExpirationDate{Time: v.TransitionDate}
+	rule.Transition.StorageClass = v.TransitionStorageClass
+	rule.NoncurrentVersionTransition.NoncurrentDays = lifecycle.ExpirationDays(v.NoncurrentTransitionDays)
+	rule.NoncurrentVersionTransition.StorageClass = v.NoncurrentTransitionStorageClass
+
+	rule.AbortIncompleteMultipartUpload.DaysAfterInitiation = lifecycle.ExpirationDays(v.AbortIncompleteUploadDays)
+	return rule
+}
+
+func (s *Storage) formatLifecycleRule(v lifecycle.Rule) LifecycleRule {
+	return LifecycleRule{
+		ID:       v.ID,
+		Path:     s.getRelPath(lifecycleRulePrefix(v)),
+		Disabled: v.Status != "Enabled",
+
+		ExpirationDays:           int(v.Expiration.Days),
+		ExpirationDate:           v.Expiration.Date.Time,
+		NoncurrentExpirationDays: int(v.NoncurrentVersionExpiration.NoncurrentDays),
+
+		TransitionDays:                   int(v.Transition.Days),
+		TransitionDate:                   v.Transition.Date.Time,
+		TransitionStorageClass:           v.Transition.StorageClass,
+		NoncurrentTransitionDays:         int(v.NoncurrentVersionTransition.NoncurrentDays),
+		NoncurrentTransitionStorageClass: v.NoncurrentVersionTransition.StorageClass,
+
+		AbortIncompleteUploadDays: int(v.AbortIncompleteMultipartUpload.DaysAfterInitiation),
+	}
+}
+
+// lifecycleRulePrefix returns the prefix from the filter or the deprecated
+// prefix field of the rule.
+func lifecycleRulePrefix(v lifecycle.Rule) string {
+	if v.RuleFilter.Prefix != "" {
+		return v.RuleFilter.Prefix
+	}
+	if v.RuleFilter.And.Prefix != "" {
+		return v.RuleFilter.And.Prefix
+	}
+	return v.Prefix
+}
+
+// normalizeLifecycleRule makes dates comparable by reflect.DeepEqual.
+func normalizeLifecycleRule(v LifecycleRule) LifecycleRule {
+	v.ExpirationDate = v.ExpirationDate.UTC()
+	v.TransitionDate = v.TransitionDate.UTC()
+	return v
+}
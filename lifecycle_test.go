@@ -0,0 +1,52 @@
+package minio
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLifecycleKeepsUnsupportedRules(t *testing.T) {
+	f := newFakeServer(t)
+	f.setConfig("bucket", "lifecycle", `<LifecycleConfiguration>
+<Rule><ID>plain</ID><Status>Enabled</Status><Filter><Prefix>w/logs/</Prefix></Filter><Expiration><Days>7</Days></Expiration></Rule>
+<Rule><ID>tag</ID><Status>Enabled</Status><Filter><And><Prefix>w/logs/</Prefix><Tag><Key>k</Key><Value>v</Value></Tag></And></Filter><Expiration><Days>1</Days></Expiration></Rule>
+<Rule><ID>size</ID><Status>Enabled</Status><Filter><And><Prefix>w/logs/</Prefix><ObjectSizeGreaterThan>1024</ObjectSizeGreaterThan></And></Filter><Expiration><Days>1</Days></Expiration></Rule>
+<Rule><ID>marker</ID><Status>Enabled</Status><Filter><Prefix>w/</Prefix></Filter><Expiration><ExpiredObjectDeleteMarker>true</ExpiredObjectDeleteMarker></Expiration></Rule>
+<Rule><ID>versions</ID><Status>Enabled</Status><Filter><Prefix>w/</Prefix></Filter><NoncurrentVersionExpiration><NoncurrentDays>1</NoncurrentDays><NewerNoncurrentVersions>3</NewerNoncurrentVersions></NoncurrentVersionExpiration></Rule>
+<Rule><ID>other</ID><Status>Enabled</Status><Filter><Prefix>x/</Prefix></Filter><Expiration><Days>1</Days></Expiration></Rule>
+</LifecycleConfiguration>`)
+	store := f.newStorage(t, "bucket", "/w/")
+
+	rules, err := store.GetLifecycle()
+	if err != nil {
+		t.Fatalf("get lifecycle: %v", err)
+	}
+	if len(rules) != 1 || rules[0].ID != "plain" || rules[0].Path != "logs/" {
+		t.Fatalf("get lifecycle returned %+v, expected the plain rule only", rules)
+	}
+
+	err = store.SetLifecycle([]LifecycleRule{{ID: "new", Path: "tmp/", ExpirationDays: 1}})
+	if err != nil {
+		t.Fatalf("set lifecycle: %v", err)
+	}
+	// Only the plain rule is replaced, others are kept in the bucket.
+	actual := lifecycleRuleIDs(f.configs["bucket?lifecycle"])
+	expected := []string{"tag", "size", "marker", "versions", "other", "new"}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("set lifecycle left rules %v, expected %v", actual, expected)
+	}
+
+	err = store.SetLifecycle([]LifecycleRule{{ID: "marker", Path: "tmp/", ExpirationDays: 1}})
+	if err == nil {
+		t.Errorf("set lifecycle with the ID of an unsupported rule should fail")
+	}
+}
+
+// lifecycleRuleIDs returns the IDs of rules in the lifecycle configuration.
+func lifecycleRuleIDs(config string) (ids []string) {
+	for _, v := range strings.Split(config, "<ID>")[1:] {
+		ids = append(ids, v[:strings.Index(v, "</ID>")])
+	}
+	return ids
+}
@@ -0,0 +1,64 @@
+package minio
+
+import (
+	"context"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// AbortIncompleteUploads will abort all incomplete multipart uploads under path
+// which were initiated more than olderThan ago, and return the number of aborted uploads.
+//
+// Incomplete uploads can be listed via List with ListModePart.
+func (s *Storage) AbortIncompleteUploads(path string, olderThan time.Duration) (n int, err error) {
+	ctx := context.Background()
+	return s.AbortIncompleteUploadsWithContext(ctx, path, olderThan)
+}
+
+// AbortIncompleteUploadsWithContext will abort all incomplete multipart uploads under path
+// which were initiated more than olderThan ago, and return the number of aborted uploads.
+func (s *Storage) AbortIncompleteUploadsWithContext(ctx context.Context, path string, olderThan time.Duration) (n int, err error) {
+	defer func() {
+		err = s.formatError("abort_incomplete_uploads", err, path)
+	}()
+
+	return s.abortIncompleteUploads(ctx, path, olderThan)
+}
+
+func (s *Storage) abortIncompleteUploads(ctx context.Context, path string, olderThan time.Duration) (n int, err error) {
+	rp := s.getAbsPath(path)
+	deadline := time.Now().Add(-olderThan)
+
+	ctx, cancel := context.WithCancel(ctx)
+	uploadChan := s.client.ListIncompleteUploads(ctx, s.bucket, rp, true)
+	defer func() {
+		cancel()
+		// Drain the channel so that the listing goroutine could exit.
+		for range uploadChan {
+		}
+	}()
+
+	// RemoveIncompleteUpload will abort all uploads of the same key, which
+	// could break an upload that is still in progress, so we abort them by
+	// upload id instead.
+	core := minio.Core{Client: s.client}
+	for v := range uploadChan {
+		if v.Err != nil {
+			return n, v.Err
+		}
+		if !v.Initiated.Before(deadline) {
+			continue
+		}
+		err = core.AbortMultipartUpload(ctx, s.bucket, v.Key, v.UploadID)
+		if err != nil {
+			// The upload could be completed or aborted by others.
+			if minio.ToErrorResponse(err).Code == "NoSuchUpload" {
+				continue
+			}
+			return n, err
+		}
+		n++
+	}
+	return n, nil
+}
@@ -0,0 +1,304 @@
+package minio
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/minio/minio-go/v7/pkg/notification"
+)
+
+// NotificationRule is a bucket notification rule for objects under Path of the Storage.
+type NotificationRule struct {
+	// ID is the identifier of the rule in the bucket, rules without ID are
+	// identified by their content.
+	ID string
+	// Target is the ARN of the notification target, like `arn:minio:sqs::primary:webhook`.
+	//
+	// The service of the ARN decides the kind of the target: `sqs` for
+	// queues, `sns` for topics and `lambda` for functions.
+	Target string
+	// Events is the S3 event names, like `s3:ObjectCreated:*`.
+	Events []string
+	// Path is the path relative to the work dir that the rule applies to.
+	Path string
+	// Suffix will only notify objects whose path ends with it, like `.jpg`.
+	Suffix string
+}
+
+// GetNotifications will get notification rules under the work dir.
+func (s *Storage) GetNotifications() (rules []NotificationRule, err error) {
+	ctx := context.Background()
+	return s.GetNotificationsWithContext(ctx)
+}
+
+// GetNotificationsWithContext will get notification rules under the work dir.
+func (s *Storage) GetNotificationsWithContext(ctx context.Context) (rules []NotificationRule, err error) {
+	defer func() {
+		err = s.formatError("get_notifications", err)
+	}()
+
+	return s.getNotifications(ctx)
+}
+
+// AddNotification will add rule to the bucket notification configuration.
+//
+// AddNotification is idempotent: a rule with the same ID under the work dir
+// will be replaced, and nothing will be changed if the same rule exists.
+// Rules of other paths in the bucket will be kept as is.
+//
+// The configuration is updated by read-modify-write, so concurrent updates
+// to the same bucket could overwrite each other.
+func (s *Storage) AddNotification(rule NotificationRule) (err error) {
+	ctx := context.Background()
+	return s.AddNotificationWithContext(ctx, rule)
+}
+
+// AddNotificationWithContext will add rule to the bucket notification configuration.
+func (s *Storage) AddNotificationWithContext(ctx context.Context, rule NotificationRule) (err error) {
+	defer func() {
+		err = s.formatError("add_notification", err, rule.Path)
+	}()
+
+	return s.addNotification(ctx, rule)
+}
+
+// RemoveNotification will remove the rule with id under the work dir.
+//
+// Nothing will happen if the rule doesn't exist.
+func (s *Storage) RemoveNotification(id string) (err error) {
+	ctx := context.Background()
+	return s.RemoveNotificationWithContext(ctx, id)
+}
+
+// RemoveNotificationWithContext will remove the rule with id under the work dir.
+func (s *Storage) RemoveNotificationWithContext(ctx context.Context, id string) (err error) {
+	defer func() {
+		err = s.formatError("remove_notification", err)
+	}()
+
+	return s.updateNotifications(ctx, func(rules []NotificationRule) ([]NotificationRule, bool) {
+		kept := rules[:0]
+		for _, v := range rules {
+			if v.ID != id {
+				kept = append(kept, v)
+			}
+		}
+		return kept, len(kept) != len(rules)
+	})
+}
+
+// RemoveAllNotifications will remove all notification rules under the work dir.
+//
+// Rules for other paths in the bucket will be kept as is.
+func (s *Storage) RemoveAllNotifications() (err error) {
+	ctx := context.Background()
+	return s.RemoveAllNotificationsWithContext(ctx)
+}
+
+// RemoveAllNotificationsWithContext will remove all notification rules under the work dir.
+func (s *Storage) RemoveAllNotificationsWithContext(ctx context.Context) (err error) {
+	defer func() {
+		err = s.formatError("remove_all_notifications", err)
+	}()
+
+	return s.updateNotifications(ctx, func(rules []NotificationRule) ([]NotificationRule, bool) {
+		return nil, len(rules) > 0
+	})
+}
+
+func (s *Storage) getNotifications(ctx context.Context) (rules []NotificationRule, err error) {
+	cfg, err := s.client.GetBucketNotification(ctx, s.bucket)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range notificationConfigs(cfg) {
+		if !s.isNotificationOwned(v) {
+			continue
+		}
+		rules = append(rules, s.formatNotificationRule(v))
+	}
+	return rules, nil
+}
+
+func (s *Storage) addNotification(ctx context.Context, rule NotificationRule) (err error) {
+	// Validate the rule before touching the bucket.
+	_, err = s.newNotificationConfig(rule)
+	if err != nil {
+		return err
+	}
+
+	return s.updateNotifications(ctx, func(rules []NotificationRule) ([]NotificationRule, bool) {
+		for i, v := range rules {
+			if rule.ID != "" && v.ID == rule.ID {
+				if s.isNotificationRuleEqual(v, rule) {
+					return rules, false
+				}
+				rules[i] = rule
+				return rules, true
+			}
+			if rule.ID == "" && s.isNotificationRuleEqual(v, rule) {
+				return rules, false
+			}
+		}
+		return append(rules, rule), true
+	})
+}
+
+// updateNotifications will replace rules under the work dir with the rules
+// returned by fn if they are changed.
+func (s *Storage) updateNotifications(ctx context.Context, fn func(rules []NotificationRule) ([]NotificationRule, bool)) (err error) {
+	current, err := s.client.GetBucketNotification(ctx, s.bucket)
+	if err != nil {
+		return err
+	}
+
+	var owned []NotificationRule
+	cfg := notification.Configuration{}
+	for _, v := range notificationConfigs(current) {
+		if s.isNotificationOwned(v) {
+			owned = append(owned, s.formatNotificationRule(v))
+			continue
+		}
+		err = addNotificationConfig(&cfg, v)
+		if err != nil {
+			return err
+		}
+	}
+
+	rules, changed := fn(owned)
+	if !changed {
+		return nil
+	}
+	for _, v := range rules {
+		nc, err := s.newNotificationConfig(v)
+		if err != nil {
+			return err
+		}
+		err = addNotificationConfig(&cfg, nc)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(cfg.QueueConfigs) == 0 && len(cfg.TopicConfigs) == 0 && len(cfg.LambdaConfigs) == 0 {
+		return s.client.RemoveAllBucketNotification(ctx, s.bucket)
+	}
+	return s.client.SetBucketNotification(ctx, s.bucket, cfg)
+}
+
+// isNotificationOwned checks whether the config applies to a path under the work dir.
+func (s *Storage) isNotificationOwned(v notification.Config) bool {
+	prefix, _ := notificationFilter(v)
+	return strings.HasPrefix(prefix, s.getAbsPath(""))
+}
+
+func (s *Storage) newNotificationConfig(v NotificationRule) (nc notification.Config, err error) {
+	arn, err := notification.NewArnFromString(v.Target)
+	if err != nil {
+		return nc, fmt.Errorf("notification target %q: %w", v.Target, err)
+	}
+	if len(v.Events) == 0 {
+		return nc, fmt.Errorf("notification rule %q doesn't have events", v.ID)
+	}
+
+	nc = notification.NewConfig(arn)
+	nc.ID = v.ID
+	for _, e := range v.Events {
+		nc.AddEvents(notification.EventType(e))
+	}
+	if prefix := s.getAbsPath(v.Path); prefix != "" {
+		nc.AddFilterPrefix(prefix)
+	}
+	if v.Suffix != "" {
+		nc.AddFilterSuffix(v.Suffix)
+	}
+	return nc, nil
+}
+
+func (s *Storage) formatNotificationRule(v notification.Config) NotificationRule {
+	prefix, suffix := notificationFilter(v)
+	rule := NotificationRule{
+		ID:     v.ID,
+		Target: v.Arn.String(),
+		Path:   s.getRelPath(prefix),
+		Suffix: suffix,
+	}
+	for _, e := range v.Events {
+		rule.Events = append(rule.Events, string(e))
+	}
+	return rule
+}
+
+// notificationConfigs flattens configs of all target kinds with their ARN filled.
+//
+// ARN in notification.Config is not unmarshalled from the response.
+func notificationConfigs(cfg notification.Configuration) (configs []notification.Config) {
+	appendConfig := func(v notification.Config, target string) {
+		// Configs with invalid ARN are kept with the zero ARN and will be
+		// rejected while being set back.
+		v.Arn, _ = notification.NewArnFromString(target)
+		configs = append(configs, v)
+	}
+	for _, v := range cfg.QueueConfigs {
+		appendConfig(v.Config, v.Queue)
+	}
+	for _, v := range cfg.TopicConfigs {
+		appendConfig(v.Config, v.Topic)
+	}
+	for _, v := range cfg.LambdaConfigs {
+		appendConfig(v.Config, v.Lambda)
+	}
+	return configs
+}
+
+// addNotificationConfig adds nc to cfg by the service of its ARN.
+//
+// Configuration.AddQueue and friends are not used because they skip configs
+// with overlapped events, which is not the same as what we compare.
+func addNotificationConfig(cfg *notification.Configuration, nc notification.Config) error {
+	target := nc.Arn.String()
+	switch nc.Arn.Service {
+	case "sqs":
+		cfg.QueueConfigs = append(cfg.QueueConfigs, notification.QueueConfig{Config: nc, Queue: target})
+	case "sns":
+		cfg.TopicConfigs = append(cfg.TopicConfigs, notification.TopicConfig{Config: nc, Topic: target})
+	case "lambda":
+		cfg.LambdaConfigs = append(cfg.LambdaConfigs, notification.LambdaConfig{Config: nc, Lambda: target})
+	default:
+		return fmt.Errorf("notification target %q has unsupported service %q", target, nc.Arn.Service)
+	}
+	return nil
+}
+
+// notificationFilter returns the prefix and suffix filter of the config.
+func notificationFilter(v notification.Config) (prefix, suffix string) {
+	if v.Filter == nil {
+		return
+	}
+	for _, r := range v.Filter.S3Key.FilterRules {
+		switch r.Name {
+		case "prefix":
+			prefix = r.Value
+		case "suffix":
+			suffix = r.Value
+		}
+	}
+	return
+}
+
+// isNotificationRuleEqual compares rules by content, and the order of events doesn't matter.
+func (s *Storage) isNotificationRuleEqual(a, b NotificationRule) bool {
+	if a.Target != b.Target || s.getAbsPath(a.Path) != s.getAbsPath(b.Path) || a.Suffix != b.Suffix {
+		return false
+	}
+	ea := make([]notification.EventType, 0, len(a.Events))
+	for _, v := range a.Events {
+		ea = append(ea, notification.EventType(v))
+	}
+	eb := make([]notification.EventType, 0, len(b.Events))
+	for _, v := range b.Events {
+		eb = append(eb, notification.EventType(v))
+	}
+	return notification.EqualEventTypeList(ea, eb)
+}
@@ -0,0 +1,145 @@
+package minio
+
+import (
+	"encoding/xml"
+	"reflect"
+	"testing"
+
+	"github.com/minio/minio-go/v7/pkg/notification"
+)
+
+const notificationTarget = "arn:minio:sqs::primary:webhook"
+
+func TestAddNotificationReplacesByID(t *testing.T) {
+	f := newFakeServer(t)
+	f.setConfig("bucket", "notification", `<NotificationConfiguration>
+<QueueConfiguration><Id>a</Id><Queue>arn:minio:sqs::primary:webhook</Queue><Event>s3:ObjectCreated:*</Event><Filter><S3Key><FilterRule><Name>prefix</Name><Value>w/</Value></FilterRule></S3Key></Filter></QueueConfiguration>
+<QueueConfiguration><Id>other</Id><Queue>arn:minio:sqs::primary:webhook</Queue><Event>s3:ObjectCreated:*</Event><Filter><S3Key><FilterRule><Name>prefix</Name><Value>x/</Value></FilterRule></S3Key></Filter></QueueConfiguration>
+</NotificationConfiguration>`)
+	store := f.newStorage(t, "bucket", "/w/")
+
+	err := store.AddNotification(NotificationRule{ID: "a", Target: notificationTarget, Events: []string{"s3:ObjectRemoved:*"}, Path: "logs/"})
+	if err != nil {
+		t.Fatalf("add notification: %v", err)
+	}
+	// Rules of other work dirs are kept before rules under the work dir.
+	configs := fakeNotificationConfigs(t, f)
+	if len(configs) != 2 {
+		t.Fatalf("add notification left %d rules, expected 2", len(configs))
+	}
+	if prefix, _ := notificationFilter(configs[0]); configs[0].ID != "other" || prefix != "x/" {
+		t.Errorf("add notification left %+v with prefix %s, expected the rule of another work dir", configs[0], prefix)
+	}
+	prefix, _ := notificationFilter(configs[1])
+	if configs[1].ID != "a" || prefix != "w/logs/" || !reflect.DeepEqual(configs[1].Events, []notification.EventType{"s3:ObjectRemoved:*"}) {
+		t.Errorf("add notification left %+v with prefix %s, expected the new rule", configs[1], prefix)
+	}
+}
+
+func TestAddNotificationIdentical(t *testing.T) {
+	f := newFakeServer(t)
+	f.setConfig("bucket", "notification", `<NotificationConfiguration>
+<QueueConfiguration><Id>a</Id><Queue>arn:minio:sqs::primary:webhook</Queue><Event>s3:ObjectCreated:*</Event><Event>s3:ObjectRemoved:*</Event><Filter><S3Key><FilterRule><Name>prefix</Name><Value>w/</Value></FilterRule><FilterRule><Name>suffix</Name><Value>.jpg</Value></FilterRule></S3Key></Filter></QueueConfiguration>
+</NotificationConfiguration>`)
+	store := f.newStorage(t, "bucket", "/w/")
+
+	// The order of events doesn't matter.
+	err := store.AddNotification(NotificationRule{ID: "a", Target: notificationTarget, Events: []string{"s3:ObjectRemoved:*", "s3:ObjectCreated:*"}, Suffix: ".jpg"})
+	if err != nil {
+		t.Fatalf("add notification: %v", err)
+	}
+	if n := f.count("PutBucketNotification"); n != 0 {
+		t.Errorf("add an identical notification sent %d PutBucketNotification requests, expected 0", n)
+	}
+}
+
+func TestAddNotificationWithoutID(t *testing.T) {
+	f := newFakeServer(t)
+	f.setConfig("bucket", "notification", `<NotificationConfiguration>
+<QueueConfiguration><Queue>arn:minio:sqs::primary:webhook</Queue><Event>s3:ObjectCreated:*</Event><Filter><S3Key><FilterRule><Name>prefix</Name><Value>w/</Value></FilterRule></S3Key></Filter></QueueConfiguration>
+</NotificationConfiguration>`)
+	store := f.newStorage(t, "bucket", "/w/")
+
+	rule := NotificationRule{Target: notificationTarget, Events: []string{"s3:ObjectCreated:*"}}
+	err := store.AddNotification(rule)
+	if err != nil {
+		t.Fatalf("add notification: %v", err)
+	}
+	if n := f.count("PutBucketNotification"); n != 0 {
+		t.Errorf("add a notification with the same content sent %d PutBucketNotification requests, expected 0", n)
+	}
+
+	// Rules with different content are added.
+	rule.Suffix = ".jpg"
+	err = store.AddNotification(rule)
+	if err != nil {
+		t.Fatalf("add notification: %v", err)
+	}
+	rules, err := store.GetNotifications()
+	if err != nil {
+		t.Fatalf("get notifications: %v", err)
+	}
+	if len(rules) != 2 || rules[0].Suffix != "" || rules[1].Suffix != ".jpg" {
+		t.Errorf("get notifications returned %+v, expected both rules", rules)
+	}
+}
+
+func TestRemoveNotifications(t *testing.T) {
+	f := newFakeServer(t)
+	f.setConfig("bucket", "notification", `<NotificationConfiguration>
+<QueueConfiguration><Id>a</Id><Queue>arn:minio:sqs::primary:webhook</Queue><Event>s3:ObjectCreated:*</Event><Filter><S3Key><FilterRule><Name>prefix</Name><Value>w/</Value></FilterRule></S3Key></Filter></QueueConfiguration>
+<QueueConfiguration><Id>b</Id><Queue>arn:minio:sqs::primary:webhook</Queue><Event>s3:ObjectCreated:*</Event><Filter><S3Key><FilterRule><Name>prefix</Name><Value>w/b/</Value></FilterRule></S3Key></Filter></QueueConfiguration>
+<QueueConfiguration><Id>other</Id><Queue>arn:minio:sqs::primary:webhook</Queue><Event>s3:ObjectCreated:*</Event><Filter><S3Key><FilterRule><Name>prefix</Name><Value>x/</Value></FilterRule></S3Key></Filter></QueueConfiguration>
+</NotificationConfiguration>`)
+	store := f.newStorage(t, "bucket", "/w/")
+
+	err := store.RemoveNotification("a")
+	if err != nil {
+		t.Fatalf("remove notification: %v", err)
+	}
+	if ids := notificationConfigIDs(fakeNotificationConfigs(t, f)); !reflect.DeepEqual(ids, []string{"other", "b"}) {
+		t.Errorf("remove notification left rules %v, expected [other b]", ids)
+	}
+
+	err = store.RemoveAllNotifications()
+	if err != nil {
+		t.Fatalf("remove all notifications: %v", err)
+	}
+	if ids := notificationConfigIDs(fakeNotificationConfigs(t, f)); !reflect.DeepEqual(ids, []string{"other"}) {
+		t.Errorf("remove all notifications left rules %v, expected [other]", ids)
+	}
+
+	// The configuration is removed while there are no rules left.
+	other := f.newStorage(t, "bucket", "/x/")
+	err = other.RemoveAllNotifications()
+	if err != nil {
+		t.Fatalf("remove all notifications: %v", err)
+	}
+	if configs := fakeNotificationConfigs(t, f); len(configs) != 0 {
+		t.Errorf("remove all notifications left rules %v, expected none", notificationConfigIDs(configs))
+	}
+	if n := f.count("PutBucketNotification"); n != 3 {
+		t.Errorf("remove notifications sent %d PutBucketNotification requests, expected 3", n)
+	}
+}
+
+// fakeNotificationConfigs returns the notification configs set in the bucket.
+func fakeNotificationConfigs(t *testing.T, f *fakeServer) []notification.Config {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var cfg notification.Configuration
+	err := xml.Unmarshal([]byte(f.configs["bucket?notification"]), &cfg)
+	if err != nil {
+		t.Fatalf("unmarshal notification configuration: %v", err)
+	}
+	return notificationConfigs(cfg)
+}
+
+// notificationConfigIDs returns the IDs of the notification configs.
+func notificationConfigIDs(configs []notification.Config) (ids []string) {
+	for _, v := range configs {
+		ids = append(ids, v.ID)
+	}
+	return ids
+}
@@ -0,0 +1,144 @@
+package minio
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/minio/minio-go/v7/pkg/policy"
+)
+
+// bucketPolicyVersion is the version of bucket policy language.
+const bucketPolicyVersion = "2012-10-17"
+
+// GetPolicy will get the policy JSON of the bucket, empty string will be
+// returned if the bucket doesn't have a policy.
+func (s *Storage) GetPolicy() (p string, err error) {
+	ctx := context.Background()
+	return s.GetPolicyWithContext(ctx)
+}
+
+// GetPolicyWithContext will get the policy JSON of the bucket.
+func (s *Storage) GetPolicyWithContext(ctx context.Context) (p string, err error) {
+	defer func() {
+		err = s.formatError("get_policy", err)
+	}()
+
+	return s.client.GetBucketPolicy(ctx, s.bucket)
+}
+
+// SetPolicy will replace the policy of the bucket with the policy JSON p,
+// empty string will remove the policy.
+func (s *Storage) SetPolicy(p string) (err error) {
+	ctx := context.Background()
+	return s.SetPolicyWithContext(ctx, p)
+}
+
+// SetPolicyWithContext will replace the policy of the bucket with the policy JSON p.
+func (s *Storage) SetPolicyWithContext(ctx context.Context, p string) (err error) {
+	defer func() {
+		err = s.formatError("set_policy", err)
+	}()
+
+	return s.client.SetBucketPolicy(ctx, s.bucket, p)
+}
+
+// GrantPublicRead will allow anonymous users to list and read objects under path.
+//
+// path is treated as a dir, so `reports` will not grant `reports-private/`.
+// Statements for other paths in the bucket policy will be kept.
+func (s *Storage) GrantPublicRead(path string) (err error) {
+	ctx := context.Background()
+	return s.GrantPublicReadWithContext(ctx, path)
+}
+
+// GrantPublicReadWithContext will allow anonymous users to list and read objects under path.
+func (s *Storage) GrantPublicReadWithContext(ctx context.Context, path string) (err error) {
+	defer func() {
+		err = s.formatError("grant_public_read", err, path)
+	}()
+
+	return s.setAnonymousPolicy(ctx, path, policy.BucketPolicyReadOnly)
+}
+
+// RevokePublicRead will remove anonymous access to objects under path.
+//
+// Statements for other paths in the bucket policy will be kept.
+func (s *Storage) RevokePublicRead(path string) (err error) {
+	ctx := context.Background()
+	return s.RevokePublicReadWithContext(ctx, path)
+}
+
+// RevokePublicReadWithContext will remove anonymous access to objects under path.
+func (s *Storage) RevokePublicReadWithContext(ctx context.Context, path string) (err error) {
+	defer func() {
+		err = s.formatError("revoke_public_read", err, path)
+	}()
+
+	return s.setAnonymousPolicy(ctx, path, policy.BucketPolicyNone)
+}
+
+func (s *Storage) setAnonymousPolicy(ctx context.Context, path string, bp policy.BucketPolicy) (err error) {
+	ap, err := s.getBucketAccessPolicy(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Policies apply to resources like `bucket/prefix*`, add the trailing
+	// slash to make sure only objects under the dir are matched.
+	prefix := s.getAbsPath(path)
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	ap.Statements = policy.SetPolicy(ap.Statements, bp, s.bucket, prefix)
+	if len(ap.Statements) == 0 {
+		return s.client.SetBucketPolicy(ctx, s.bucket, "")
+	}
+
+	content, err := json.Marshal(ap)
+	if err != nil {
+		return err
+	}
+	return s.client.SetBucketPolicy(ctx, s.bucket, string(content))
+}
+
+// isPublicRead checks whether anonymous users could read the object at rp.
+func (s *Storage) isPublicRead(ctx context.Context, rp string) (bool, error) {
+	ap, err := s.getBucketAccessPolicy(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	// GetPolicies returns policies keyed by resources like `bucket/prefix*`.
+	for resource, bp := range policy.GetPolicies(ap.Statements, s.bucket, "") {
+		if bp != policy.BucketPolicyReadOnly && bp != policy.BucketPolicyReadWrite {
+			continue
+		}
+		resource = strings.TrimPrefix(resource, s.bucket+"/")
+		if strings.HasSuffix(resource, "*") {
+			if strings.HasPrefix(rp, strings.TrimSuffix(resource, "*")) {
+				return true, nil
+			}
+		} else if rp == resource {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *Storage) getBucketAccessPolicy(ctx context.Context) (ap policy.BucketAccessPolicy, err error) {
+	content, err := s.client.GetBucketPolicy(ctx, s.bucket)
+	if err != nil {
+		return ap, err
+	}
+
+	ap.Version = bucketPolicyVersion
+	if content == "" {
+		return ap, nil
+	}
+	err = json.Unmarshal([]byte(content), &ap)
+	if err != nil {
+		return ap, err
+	}
+	return ap, nil
+}
@@ -0,0 +1,89 @@
+package minio
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	ps "github.com/beyondstorage/go-storage/v4/pairs"
+)
+
+func TestGrantPublicReadDir(t *testing.T) {
+	f := newFakeServer(t)
+	store := f.newStorage(t, "bucket", "/w/")
+
+	err := store.GrantPublicRead("reports")
+	if err != nil {
+		t.Fatalf("grant public read: %v", err)
+	}
+	p, err := store.GetPolicy()
+	if err != nil {
+		t.Fatalf("get policy: %v", err)
+	}
+	if !strings.Contains(p, `bucket/w/reports/*`) || strings.Contains(p, `bucket/w/reports*`) {
+		t.Errorf("policy %s should grant objects under the dir only", p)
+	}
+
+	for path, public := range map[string]bool{
+		"w/reports/a":         true,
+		"w/reports/b/c":       true,
+		"w/reports-private/a": false,
+		"w/reports":           false,
+	} {
+		actual, err := store.isPublicRead(context.Background(), path)
+		if err != nil {
+			t.Fatalf("check public read: %v", err)
+		}
+		if actual != public {
+			t.Errorf("%s is public %v, expected %v", path, actual, public)
+		}
+	}
+
+	err = store.RevokePublicRead("reports/")
+	if err != nil {
+		t.Fatalf("revoke public read: %v", err)
+	}
+	if _, ok := f.configs["bucket?policy"]; ok {
+		t.Errorf("policy should be removed after the only grant is revoked")
+	}
+}
+
+func TestReachPublicURL(t *testing.T) {
+	f := newFakeServer(t)
+	store := f.newStorage(t, "bucket", "/w/")
+	err := store.GrantPublicRead("reports")
+	if err != nil {
+		t.Fatalf("grant public read: %v", err)
+	}
+
+	// Presigned urls are returned without checking the bucket policy.
+	before := f.count("GetBucketPolicy")
+	u, err := store.Reach("reports/a")
+	if err != nil {
+		t.Fatalf("reach: %v", err)
+	}
+	if !strings.Contains(u, "X-Amz-Signature=") {
+		t.Errorf("reach returned %s, expected a presigned url", u)
+	}
+	if n := f.count("GetBucketPolicy") - before; n != 0 {
+		t.Errorf("reach sent %d GetBucketPolicy requests, expected 0", n)
+	}
+
+	u, err = store.Reach("reports/a", WithPublicURL())
+	if err != nil {
+		t.Fatalf("reach: %v", err)
+	}
+	if expected := f.URL + "/bucket/w/reports/a"; u != expected {
+		t.Errorf("reach returned %s, expected %s", u, expected)
+	}
+
+	_, err = store.Reach("reports-private/a", WithPublicURL())
+	if err == nil {
+		t.Errorf("reach private object via the unsigned url should fail")
+	}
+	_, err = store.Reach("reports/a", WithPublicURL(), ps.WithExpire(time.Minute))
+	if err == nil {
+		t.Errorf("reach via the unsigned url with expire should fail")
+	}
+}
@@ -0,0 +1,106 @@
+package minio
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/minio/minio-go/v7"
+
+	"github.com/beyondstorage/go-storage/v4/pkg/iowrap"
+)
+
+// QueryOptions is the options for Query.
+type QueryOptions struct {
+	// Input describes the format of the object, exactly one of CSV, JSON and
+	// Parquet must be set.
+	Input minio.SelectObjectInputSerialization
+	// Output describes the format of the results, default to CSV for CSV
+	// objects and JSON lines for others.
+	Output minio.SelectObjectOutputSerialization
+	// IoCallback will be called with the results written to w.
+	IoCallback func([]byte)
+}
+
+// QueryStats is the bytes stats of a query.
+type QueryStats struct {
+	// BytesScanned is the bytes of the object scanned, which is compressed
+	// if the object is compressed.
+	BytesScanned int64
+	// BytesProcessed is the uncompressed bytes processed.
+	BytesProcessed int64
+	// BytesReturned is the bytes of the results.
+	BytesReturned int64
+}
+
+// Query will run the SQL expression over the object at path via S3 Select
+// and write the results into w.
+//
+// The expression refers to the object as `S3Object`, like
+// `SELECT s.name FROM S3Object s WHERE s.age > 18`.
+func (s *Storage) Query(path string, expression string, w io.Writer, opt QueryOptions) (stats QueryStats, err error) {
+	ctx := context.Background()
+	return s.QueryWithContext(ctx, path, expression, w, opt)
+}
+
+// QueryWithContext will run the SQL expression over the object at path via
+// S3 Select and write the results into w.
+func (s *Storage) QueryWithContext(ctx context.Context, path string, expression string, w io.Writer, opt QueryOptions) (stats QueryStats, err error) {
+	defer func() {
+		err = s.formatError("query", err, path)
+	}()
+
+	return s.query(ctx, path, expression, w, opt)
+}
+
+func (s *Storage) query(ctx context.Context, path string, expression string, w io.Writer, opt QueryOptions) (stats QueryStats, err error) {
+	rp := s.getAbsPath(path)
+
+	input := opt.Input
+	if input.CSV == nil && input.JSON == nil && input.Parquet == nil {
+		return QueryStats{}, fmt.Errorf("input format of query is not specified")
+	}
+	output := opt.Output
+	if output.CSV == nil && output.JSON == nil {
+		if input.CSV != nil {
+			output.CSV = &minio.CSVOutputOptions{}
+		} else {
+			output.JSON = &minio.JSONOutputOptions{}
+		}
+	}
+
+	options := minio.SelectObjectOptions{
+		Expression:          expression,
+		ExpressionType:      minio.QueryExpressionTypeSQL,
+		InputSerialization:  input,
+		OutputSerialization: output,
+	}
+	results, err := s.client.SelectObjectContent(ctx, s.bucket, rp, options)
+	if err != nil {
+		return QueryStats{}, err
+	}
+	defer func() {
+		cerr := results.Close()
+		if cerr != nil && err == nil {
+			err = cerr
+		}
+	}()
+
+	var r io.Reader = results
+	if opt.IoCallback != nil {
+		r = iowrap.CallbackReader(r, opt.IoCallback)
+	}
+	_, err = io.Copy(w, r)
+	if err != nil {
+		return QueryStats{}, err
+	}
+
+	// Stats is sent at the end of the results, and is available after
+	// all results have been read.
+	st := results.Stats()
+	return QueryStats{
+		BytesScanned:   st.BytesScanned,
+		BytesProcessed: st.BytesProcessed,
+		BytesReturned:  st.BytesReturned,
+	}, nil
+}
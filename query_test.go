@@ -0,0 +1,67 @@
+package minio
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/minio/minio-go/v7"
+)
+
+func TestQuery(t *testing.T) {
+	f := newFakeServer(t)
+	f.put("bucket", "w/a", `{"name":"a"}`)
+	store := f.newStorage(t, "bucket", "/w/")
+
+	cases := []struct {
+		name   string
+		opt    QueryOptions
+		output string
+	}{
+		{"csv", QueryOptions{Input: minio.SelectObjectInputSerialization{CSV: &minio.CSVInputOptions{}}}, "<CSV"},
+		{"json", QueryOptions{Input: minio.SelectObjectInputSerialization{JSON: &minio.JSONInputOptions{}}}, "<JSON"},
+		{"parquet", QueryOptions{Input: minio.SelectObjectInputSerialization{Parquet: &minio.ParquetInputOptions{}}}, "<JSON"},
+		{"csv to json", QueryOptions{
+			Input:  minio.SelectObjectInputSerialization{CSV: &minio.CSVInputOptions{}},
+			Output: minio.SelectObjectOutputSerialization{JSON: &minio.JSONOutputOptions{}},
+		}, "<JSON"},
+	}
+	for _, tc := range cases {
+		var buf bytes.Buffer
+		var called int
+		tc.opt.IoCallback = func(b []byte) {
+			called += len(b)
+		}
+		stats, err := store.Query("a", "SELECT * FROM S3Object", &buf, tc.opt)
+		if err != nil {
+			t.Fatalf("query %s: %v", tc.name, err)
+		}
+		if buf.String() != `{"name":"a"}` || called != buf.Len() {
+			t.Errorf("query %s returned %q with %d bytes called back", tc.name, buf.String(), called)
+		}
+		if expected := (QueryStats{BytesScanned: 12, BytesProcessed: 24, BytesReturned: 12}); !reflect.DeepEqual(stats, expected) {
+			t.Errorf("query %s returned stats %+v, expected %+v", tc.name, stats, expected)
+		}
+
+		body := string(f.bodies["SelectObjectContent"])
+		output := body[strings.Index(body, "<OutputSerialization>"):]
+		if !strings.HasPrefix(output, "<OutputSerialization>"+tc.output) {
+			t.Errorf("query %s sent output serialization %s, expected %s", tc.name, output, tc.output)
+		}
+	}
+}
+
+func TestQueryWithoutInputFormat(t *testing.T) {
+	f := newFakeServer(t)
+	f.put("bucket", "a", "a")
+	store := f.newStorage(t, "bucket", "/")
+
+	_, err := store.Query("a", "SELECT * FROM S3Object", &bytes.Buffer{}, QueryOptions{})
+	if err == nil || !strings.Contains(err.Error(), "input format of query is not specified") {
+		t.Errorf("query returned %v, expected the input format is not specified", err)
+	}
+	if n := f.count("SelectObjectContent"); n != 0 {
+		t.Errorf("query sent %d requests, expected 0", n)
+	}
+}
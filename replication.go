@@ -0,0 +1,236 @@
+package minio
+
+import (
+	"context"
+	"strings"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/replication"
+
+	"github.com/beyondstorage/go-storage/v4/types"
+)
+
+// ReplicationConfig is the replication configuration of a bucket.
+type ReplicationConfig struct {
+	// Role is the ARN of the IAM role, it's not required by MinIO.
+	Role  string
+	Rules []ReplicationRule
+}
+
+// ReplicationRule is a replication rule for objects under Prefix of the bucket.
+//
+// Tag filters are not supported.
+type ReplicationRule struct {
+	ID string
+	// Priority decides which rule applies while multiple rules match an
+	// object, higher priority wins.
+	Priority int
+	// Disabled will keep the rule in the bucket without applying it.
+	Disabled bool
+	// Prefix is the object key prefix in the bucket, it's not based on
+	// the work dir of any Storage.
+	Prefix string
+
+	// Destination is the ARN of the target bucket, like
+	// `arn:minio:replication::<id>:<bucket>` returned by `mc admin bucket remote add`.
+	Destination string
+	// StorageClass is the storage class of replicas, default to the source's.
+	StorageClass string
+
+	// DeleteMarkerReplication will replicate delete markers.
+	DeleteMarkerReplication bool
+	// DeleteReplication will replicate versioned deletes, this is a MinIO extension.
+	DeleteReplication bool
+	// ExistingObjectReplication will replicate objects created before the rule.
+	ExistingObjectReplication bool
+	// ReplicaModifications will replicate metadata changes of replicas back.
+	ReplicaModifications bool
+}
+
+// ReplicationReport is the objects not replicated yet under a path.
+type ReplicationReport struct {
+	// Pending is the objects waiting to be replicated.
+	Pending []*types.Object
+	// Failed is the objects failed to be replicated, they will be retried by
+	// MinIO's scanner.
+	Failed []*types.Object
+}
+
+// GetReplication will get the replication configuration of bucket name.
+//
+// An empty configuration will be returned if the bucket doesn't have one.
+func (s *Service) GetReplication(name string) (cfg ReplicationConfig, err error) {
+	ctx := context.Background()
+	return s.GetReplicationWithContext(ctx, name)
+}
+
+// GetReplicationWithContext will get the replication configuration of bucket name.
+func (s *Service) GetReplicationWithContext(ctx context.Context, name string) (cfg ReplicationConfig, err error) {
+	defer func() {
+		err = s.formatError("get_replication", err, name)
+	}()
+
+	return s.getReplication(ctx, name)
+}
+
+// SetReplication will replace the replication configuration of bucket name.
+//
+// Versioning must be enabled on both the bucket and the destination buckets.
+func (s *Service) SetReplication(name string, cfg ReplicationConfig) (err error) {
+	ctx := context.Background()
+	return s.SetReplicationWithContext(ctx, name, cfg)
+}
+
+// SetReplicationWithContext will replace the replication configuration of bucket name.
+func (s *Service) SetReplicationWithContext(ctx context.Context, name string, cfg ReplicationConfig) (err error) {
+	defer func() {
+		err = s.formatError("set_replication", err, name)
+	}()
+
+	return s.service.SetBucketReplication(ctx, name, newReplicationConfig(cfg))
+}
+
+// RemoveReplication will remove the replication configuration of bucket name.
+func (s *Service) RemoveReplication(name string) (err error) {
+	ctx := context.Background()
+	return s.RemoveReplicationWithContext(ctx, name)
+}
+
+// RemoveReplicationWithContext will remove the replication configuration of bucket name.
+func (s *Service) RemoveReplicationWithContext(ctx context.Context, name string) (err error) {
+	defer func() {
+		err = s.formatError("remove_replication", err, name)
+	}()
+
+	return s.service.RemoveBucketReplication(ctx, name)
+}
+
+// ReportReplication will walk all objects under path and report objects
+// whose replication is pending or failed.
+//
+// The replication status of every object could also be got from
+// ObjectSystemMetadata.ReplicationStatus returned by Stat.
+func (s *Storage) ReportReplication(path string) (r *ReplicationReport, err error) {
+	ctx := context.Background()
+	return s.ReportReplicationWithContext(ctx, path)
+}
+
+// ReportReplicationWithContext will walk all objects under path and report
+// objects whose replication is pending or failed.
+func (s *Storage) ReportReplicationWithContext(ctx context.Context, path string) (r *ReplicationReport, err error) {
+	defer func() {
+		err = s.formatError("report_replication", err, path)
+	}()
+
+	return s.reportReplication(ctx, path)
+}
+
+func (s *Service) getReplication(ctx context.Context, name string) (cfg ReplicationConfig, err error) {
+	output, err := s.service.GetBucketReplication(ctx, name)
+	if err != nil {
+		if minio.ToErrorResponse(err).Code == "ReplicationConfigurationNotFoundError" {
+			return ReplicationConfig{}, nil
+		}
+		return ReplicationConfig{}, err
+	}
+
+	cfg.Role = output.Role
+	for _, v := range output.Rules {
+		cfg.Rules = append(cfg.Rules, formatReplicationRule(v))
+	}
+	return cfg, nil
+}
+
+func (s *Storage) reportReplication(ctx context.Context, path string) (r *ReplicationReport, err error) {
+	options := minio.ListObjectsOptions{
+		Prefix:    s.getAbsPath(path),
+		Recursive: true,
+		// Replication status is only returned with metadata.
+		WithMetadata: true,
+	}
+
+	r = &ReplicationReport{}
+	err = s.walkObjects(ctx, options, func(v minio.ObjectInfo) error {
+		if v.ReplicationStatus == "" {
+			for k, value := range v.UserMetadata {
+				if strings.EqualFold(k, "X-Amz-Replication-Status") {
+					v.ReplicationStatus = value
+				}
+			}
+		}
+		status := minio.ReplicationStatus(v.ReplicationStatus)
+		if status != minio.ReplicationStatusPending && status != minio.ReplicationStatusFailed {
+			return nil
+		}
+
+		formatListedMetadata(&v)
+		o, err := s.formatFileObject(v)
+		if err != nil {
+			return err
+		}
+		if status == minio.ReplicationStatusPending {
+			r.Pending = append(r.Pending, o)
+		} else {
+			r.Failed = append(r.Failed, o)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func newReplicationConfig(cfg ReplicationConfig) replication.Config {
+	output := replication.Config{
+		Role: cfg.Role,
+	}
+	for _, v := range cfg.Rules {
+		rule := replication.Rule{
+			ID:       v.ID,
+			Status:   replicationStatus(!v.Disabled),
+			Priority: v.Priority,
+			Filter: replication.Filter{
+				Prefix: v.Prefix,
+			},
+			Destination: replication.Destination{
+				Bucket:       v.Destination,
+				StorageClass: v.StorageClass,
+			},
+		}
+		rule.DeleteMarkerReplication.Status = replicationStatus(v.DeleteMarkerReplication)
+		rule.DeleteReplication.Status = replicationStatus(v.DeleteReplication)
+		rule.ExistingObjectReplication.Status = replicationStatus(v.ExistingObjectReplication)
+		rule.SourceSelectionCriteria.ReplicaModifications.Status = replicationStatus(v.ReplicaModifications)
+		output.Rules = append(output.Rules, rule)
+	}
+	return output
+}
+
+func formatReplicationRule(v replication.Rule) ReplicationRule {
+	prefix := v.Filter.Prefix
+	if prefix == "" {
+		prefix = v.Filter.And.Prefix
+	}
+	return ReplicationRule{
+		ID:       v.ID,
+		Priority: v.Priority,
+		Disabled: v.Status != replication.Enabled,
+		Prefix:   prefix,
+
+		Destination:  v.Destination.Bucket,
+		StorageClass: v.Destination.StorageClass,
+
+		DeleteMarkerReplication:   v.DeleteMarkerReplication.Status == replication.Enabled,
+		DeleteReplication:         v.DeleteReplication.Status == replication.Enabled,
+		ExistingObjectReplication: v.ExistingObjectReplication.Status == replication.Enabled,
+		ReplicaModifications:      v.SourceSelectionCriteria.ReplicaModifications.Status == replication.Enabled,
+	}
+}
+
+func replicationStatus(enabled bool) replication.Status {
+	if enabled {
+		return replication.Enabled
+	}
+	return replication.Disabled
+}
@@ -0,0 +1,88 @@
+package minio
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReplicationConfig(t *testing.T) {
+	f := newFakeServer(t)
+	store := f.newStorage(t, "bucket", "/")
+	srv := &Service{service: store.client}
+
+	cfg, err := srv.GetReplication("bucket")
+	if err != nil {
+		t.Fatalf("get replication: %v", err)
+	}
+	if len(cfg.Rules) != 0 {
+		t.Errorf("get replication returned %+v, expected no rules", cfg)
+	}
+
+	expected := ReplicationConfig{
+		Rules: []ReplicationRule{
+			{
+				ID:                        "a",
+				Priority:                  1,
+				Prefix:                    "a/",
+				Destination:               "arn:minio:replication::id:backup",
+				StorageClass:              "STANDARD",
+				DeleteMarkerReplication:   true,
+				DeleteReplication:         true,
+				ExistingObjectReplication: true,
+				ReplicaModifications:      true,
+			},
+			{
+				ID:          "b",
+				Priority:    2,
+				Disabled:    true,
+				Prefix:      "b/",
+				Destination: "arn:minio:replication::id:backup",
+			},
+		},
+	}
+	err = srv.SetReplication("bucket", expected)
+	if err != nil {
+		t.Fatalf("set replication: %v", err)
+	}
+	cfg, err = srv.GetReplication("bucket")
+	if err != nil {
+		t.Fatalf("get replication: %v", err)
+	}
+	if !reflect.DeepEqual(cfg, expected) {
+		t.Errorf("get replication returned %+v, expected %+v", cfg, expected)
+	}
+}
+
+func TestReportReplication(t *testing.T) {
+	f := newFakeServer(t)
+	for k, status := range map[string]string{
+		"w/completed": "COMPLETED",
+		"w/pending":   "PENDING",
+		"w/failed":    "FAILED",
+		"w/replica":   "REPLICA",
+		"w/none":      "",
+		"x/failed":    "FAILED",
+	} {
+		f.put("bucket", k, "").replicationStatus = status
+	}
+	store := f.newStorage(t, "bucket", "/w/")
+
+	r, err := store.ReportReplication("")
+	if err != nil {
+		t.Fatalf("report replication: %v", err)
+	}
+	if len(r.Pending) != 1 || r.Pending[0].Path != "pending" {
+		t.Errorf("report replication returned pending %v, expected [pending]", r.Pending)
+	}
+	if len(r.Failed) != 1 || r.Failed[0].Path != "failed" {
+		t.Errorf("report replication returned failed %v, expected [failed]", r.Failed)
+	}
+
+	o, err := store.Stat("failed")
+	if err != nil {
+		t.Fatalf("stat: %v", err)
+	}
+	if v := GetObjectSystemMetadata(o).ReplicationStatus; v != "FAILED" {
+		t.Errorf("stat returned replication status %q, expected FAILED", v)
+	}
+}
@@ -0,0 +1,61 @@
+package minio
+
+import (
+	"context"
+
+	"github.com/minio/minio-go/v7"
+)
+
+const defaultRestoreDays = 1
+
+// RestoreOptions is the options for Restore.
+type RestoreOptions struct {
+	// Days is the days that the restored copy will be kept, default to 1.
+	Days int
+	// Tier is the retrieval tier, could be `Standard`, `Bulk` or `Expedited`,
+	// default to the tier of the storage class.
+	Tier string
+}
+
+// Restore will restore an archived object at path, so that it could be read.
+//
+// Restore returns once the restore request is accepted, the progress could be
+// checked via RestoreOngoing and RestoreExpiryTime of ObjectSystemMetadata
+// returned by Stat. Restoring an object that is being restored is not an error.
+func (s *Storage) Restore(path string, opt RestoreOptions) (err error) {
+	ctx := context.Background()
+	return s.RestoreWithContext(ctx, path, opt)
+}
+
+// RestoreWithContext will restore an archived object at path, so that it could be read.
+func (s *Storage) RestoreWithContext(ctx context.Context, path string, opt RestoreOptions) (err error) {
+	defer func() {
+		err = s.formatError("restore", err, path)
+	}()
+
+	return s.restore(ctx, path, opt)
+}
+
+func (s *Storage) restore(ctx context.Context, path string, opt RestoreOptions) (err error) {
+	rp := s.getAbsPath(path)
+
+	req := minio.RestoreRequest{}
+	req.SetDays(defaultRestoreDays)
+	if opt.Days > 0 {
+		req.SetDays(opt.Days)
+	}
+	if opt.Tier != "" {
+		req.SetGlacierJobParameters(minio.GlacierJobParameters{
+			Tier: minio.TierType(opt.Tier),
+		})
+	}
+
+	err = s.client.RestoreObject(ctx, s.bucket, rp, "", req)
+	if err != nil {
+		if minio.ToErrorResponse(err).Code == "RestoreAlreadyInProgress" {
+			return nil
+		}
+		return err
+	}
+	return nil
+}
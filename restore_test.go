@@ -0,0 +1,36 @@
+package minio
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestRestore(t *testing.T) {
+	f := newFakeServer(t)
+	f.put("bucket", "w/a", "a")
+	store := f.newStorage(t, "bucket", "/w/")
+
+	err := store.Restore("a", RestoreOptions{})
+	if err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	if body := string(f.bodies["RestoreObject"]); !strings.Contains(body, "<Days>1</Days>") || strings.Contains(body, "<Tier>") {
+		t.Errorf("restore sent %s, expected 1 day without tier", body)
+	}
+
+	err = store.Restore("a", RestoreOptions{Days: 7, Tier: "Bulk"})
+	if err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	if body := string(f.bodies["RestoreObject"]); !strings.Contains(body, "<Days>7</Days>") || !strings.Contains(body, "<Tier>Bulk</Tier>") {
+		t.Errorf("restore sent %s, expected 7 days with tier Bulk", body)
+	}
+
+	// Restoring an object that is being restored is not an error.
+	f.fail("RestoreObject", http.StatusConflict, "RestoreAlreadyInProgress")
+	err = store.Restore("a", RestoreOptions{})
+	if err != nil {
+		t.Errorf("restore in progress returned %v", err)
+	}
+}
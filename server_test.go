@@ -0,0 +1,861 @@
+package minio
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	ps "github.com/beyondstorage/go-storage/v4/pairs"
+	"github.com/beyondstorage/go-storage/v4/types"
+)
+
+// fakeObject is an object stored in fakeServer.
+type fakeObject struct {
+	content      []byte
+	etag         string
+	lastModified time.Time
+	contentType  string
+	storageClass string
+	userMetadata map[string]string
+	// replicationStatus is returned as `X-Amz-Replication-Status`.
+	replicationStatus string
+	// owner is returned as both the ID and the display name of the owner
+	// while listing with `fetch-owner`.
+	owner string
+	// headers is the extra headers returned by HeadObject and GetObject.
+	headers map[string]string
+}
+
+// fakeUpload is an incomplete multipart upload in fakeServer.
+type fakeUpload struct {
+	Key          string
+	UploadID     string `xml:"UploadId"`
+	Initiated    string
+	StorageClass string
+}
+
+// fakeServer is an in-memory S3 server which supports just enough APIs for
+// unit tests: bucket create, remove, location and configurations,
+// ListObjectsV2, ListMultipartUploads, AbortMultipartUpload,
+// ListenBucketNotification and object get, restore,
+// head, put, copy and delete. Requests are not authenticated.
+type fakeServer struct {
+	*httptest.Server
+
+	mu      sync.Mutex
+	objects map[string]map[string]*fakeObject
+	// uploads is incomplete multipart uploads by bucket, sorted by key and
+	// initiated time.
+	uploads map[string][]fakeUpload
+	buckets map[string]bool
+	// configs is the XML configurations of buckets by the bucket name and
+	// the subresource, like `bucket?versioning`.
+	configs map[string]string
+	// failures is the errors returned by the API name.
+	failures map[string]fakeError
+	// requests counts requests by the API name.
+	requests map[string]int
+	// queries is the query of the latest request by the API name.
+	queries map[string]url.Values
+	// bodies is the body of the latest request by the API name.
+	bodies map[string][]byte
+	// headers is the header of the latest request by the API name.
+	headers map[string]http.Header
+	// notifications is the JSON lines of ListenBucketNotification streams
+	// by the order of requests, and the last one is sent for the rest. All
+	// streams but the last one are dropped without being finished.
+	notifications [][]string
+	// stalls is the channels that requests of the API name wait for.
+	stalls map[string]chan struct{}
+}
+
+func newFakeServer(t *testing.T) *fakeServer {
+	f := &fakeServer{
+		objects:  make(map[string]map[string]*fakeObject),
+		uploads:  make(map[string][]fakeUpload),
+		buckets:  make(map[string]bool),
+		configs:  make(map[string]string),
+		failures: make(map[string]fakeError),
+		requests: make(map[string]int),
+		queries:  make(map[string]url.Values),
+		bodies:   make(map[string][]byte),
+		headers:  make(map[string]http.Header),
+		stalls:   make(map[string]chan struct{}),
+	}
+	f.Server = httptest.NewServer(http.HandlerFunc(f.serveHTTP))
+	t.Cleanup(f.Close)
+	return f
+}
+
+// newStorage creates a Storage which connects to the server.
+func (f *fakeServer) newStorage(t *testing.T, bucket, workDir string) *Storage {
+	_, store, err := newServicerAndStorager(
+		ps.WithCredential("hmac:ak:sk"),
+		ps.WithEndpoint("http:"+strings.TrimPrefix(f.URL, "http://")),
+		ps.WithName(bucket),
+		ps.WithWorkDir(workDir),
+	)
+	if err != nil {
+		t.Fatalf("new storager: %v", err)
+	}
+	return store
+}
+
+// put stores an object with content directly.
+func (f *fakeServer) put(bucket, key, content string) *fakeObject {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.putLocked(bucket, key, &fakeObject{
+		content:      []byte(content),
+		lastModified: time.Now().UTC().Truncate(time.Second),
+	})
+}
+
+func (f *fakeServer) putLocked(bucket, key string, o *fakeObject) *fakeObject {
+	sum := md5.Sum(o.content)
+	o.etag = hex.EncodeToString(sum[:])
+	if f.objects[bucket] == nil {
+		f.objects[bucket] = make(map[string]*fakeObject)
+	}
+	f.objects[bucket][key] = o
+	return o
+}
+
+// initiate adds an incomplete multipart upload initiated at the time.
+func (f *fakeServer) initiate(bucket, key, uploadID string, initiated time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.uploads[bucket] = append(f.uploads[bucket], fakeUpload{
+		Key:          key,
+		UploadID:     uploadID,
+		Initiated:    initiated.UTC().Format("2006-01-02T15:04:05.000Z"),
+		StorageClass: "STANDARD",
+	})
+	sort.SliceStable(f.uploads[bucket], func(i, j int) bool {
+		return f.uploads[bucket][i].Key < f.uploads[bucket][j].Key
+	})
+}
+
+// fakeError is an error response of fakeServer.
+type fakeError struct {
+	status int
+	code   string
+}
+
+// fail makes requests of the API fail with the error code.
+func (f *fakeServer) fail(api string, status int, code string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.failures[api] = fakeError{status: status, code: code}
+}
+
+// stall makes requests of the API hang until the test is finished.
+func (f *fakeServer) stall(t *testing.T, api string) {
+	ch := make(chan struct{})
+	// Registered after closing the server, so it runs before that.
+	t.Cleanup(func() { close(ch) })
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.stalls[api] = ch
+}
+
+// setConfig sets the XML configuration of the bucket subresource.
+func (f *fakeServer) setConfig(bucket, subresource, config string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.configs[bucket+"?"+subresource] = config
+}
+
+// get returns the object stored at key.
+func (f *fakeServer) get(bucket, key string) (*fakeObject, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	o, ok := f.objects[bucket][key]
+	return o, ok
+}
+
+// keys returns sorted keys in the bucket.
+func (f *fakeServer) keys(bucket string) []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.keysLocked(bucket)
+}
+
+func (f *fakeServer) keysLocked(bucket string) []string {
+	keys := make([]string, 0, len(f.objects[bucket]))
+	for k := range f.objects[bucket] {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// query returns the query of the latest request of the API.
+func (f *fakeServer) query(api string) url.Values {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.queries[api]
+}
+
+// count returns the number of requests of the API.
+func (f *fakeServer) count(api string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.requests[api]
+}
+
+func (f *fakeServer) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	bucket, key := r.URL.Path[1:], ""
+	if i := strings.Index(bucket, "/"); i >= 0 {
+		bucket, key = bucket[:i], bucket[i+1:]
+	}
+	query := r.URL.Query()
+	api := fakeAPI(r, key, query)
+	f.mu.Lock()
+	stall := f.stalls[api]
+	f.mu.Unlock()
+	if stall != nil {
+		<-stall
+	}
+	// Read the body before locking, which may be streamed from another
+	// request to the server.
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeFakeError(w, http.StatusBadRequest, "IncompleteBody")
+		return
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.requests[api]++
+	f.queries[api] = query
+	f.bodies[api] = body
+	f.headers[api] = r.Header
+	if e, ok := f.failures[api]; ok {
+		writeFakeError(w, e.status, e.code)
+		return
+	}
+
+	sub := fakeSubresource(query)
+	switch api {
+	case "GetBucketLocation":
+		writeXML(w, http.StatusOK, struct {
+			XMLName xml.Name `xml:"LocationConstraint"`
+			Value   string   `xml:",chardata"`
+		}{Value: "us-east-1"})
+	case "MakeBucket":
+		if f.buckets[bucket] {
+			writeFakeError(w, http.StatusConflict, "BucketAlreadyOwnedByYou")
+			return
+		}
+		f.buckets[bucket] = true
+	case "RemoveBucket":
+		if len(f.objects[bucket]) > 0 {
+			writeFakeError(w, http.StatusConflict, "BucketNotEmpty")
+			return
+		}
+		delete(f.buckets, bucket)
+		for k := range fakeSubresources {
+			delete(f.configs, bucket+"?"+k)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	case "GetBucket" + fakeSubresources[sub]:
+		config, ok := f.configs[bucket+"?"+sub]
+		if !ok && sub == "notification" {
+			// An empty configuration is returned for notification instead.
+			config, ok = "<NotificationConfiguration></NotificationConfiguration>", true
+		}
+		if !ok {
+			writeFakeError(w, http.StatusNotFound, fakeConfigNotFound[sub])
+			return
+		}
+		w.Header().Set("Content-Type", "application/xml")
+		w.Write([]byte(config))
+	case "PutBucket" + fakeSubresources[sub]:
+		config, _ := io.ReadAll(r.Body)
+		f.configs[bucket+"?"+sub] = string(config)
+	case "DeleteBucket" + fakeSubresources[sub]:
+		delete(f.configs, bucket+"?"+sub)
+		w.WriteHeader(http.StatusNoContent)
+	case "ListMultipartUploads":
+		f.listMultipartUploads(w, bucket, query)
+	case "ListenBucketNotification":
+		f.listenBucketNotification(w)
+	case "AbortMultipartUpload":
+		f.abortMultipartUpload(w, bucket, key, query.Get("uploadId"))
+	case "ListObjectsV2":
+		f.listObjectsV2(w, bucket, query)
+	case "HeadObject":
+		f.headObject(w, r, bucket, key, false)
+	case "GetObject":
+		f.headObject(w, r, bucket, key, true)
+	case "CopyObject":
+		f.copyObject(w, r, bucket, key)
+	case "PutObject":
+		f.putObject(w, r, bucket, key)
+	case "DeleteObject":
+		delete(f.objects[bucket], key)
+		w.WriteHeader(http.StatusNoContent)
+	case "SelectObjectContent":
+		f.selectObjectContent(w, bucket, key)
+	case "RestoreObject":
+		if _, ok := f.objects[bucket][key]; !ok {
+			writeFakeError(w, http.StatusNotFound, "NoSuchKey")
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	default:
+		writeFakeError(w, http.StatusNotImplemented, "NotImplemented")
+	}
+}
+
+// fakeAPI returns the name of the API that the request calls.
+func fakeAPI(r *http.Request, key string, query url.Values) string {
+	if key == "" {
+		if sub := fakeSubresource(query); sub != "" {
+			return fakeMethods[r.Method] + "Bucket" + fakeSubresources[sub]
+		}
+		switch {
+		case r.Method == http.MethodGet && query.Has("location"):
+			return "GetBucketLocation"
+		case r.Method == http.MethodGet && query.Has("uploads"):
+			return "ListMultipartUploads"
+		case r.Method == http.MethodGet && query.Has("events"):
+			return "ListenBucketNotification"
+		case r.Method == http.MethodGet && query.Get("list-type") == "2":
+			return "ListObjectsV2"
+		case r.Method == http.MethodPut:
+			return "MakeBucket"
+		case r.Method == http.MethodDelete:
+			return "RemoveBucket"
+		}
+		return ""
+	}
+
+	switch r.Method {
+	case http.MethodHead:
+		return "HeadObject"
+	case http.MethodGet:
+		return "GetObject"
+	case http.MethodPut:
+		if r.Header.Get("X-Amz-Copy-Source") != "" {
+			return "CopyObject"
+		}
+		return "PutObject"
+	case http.MethodPost:
+		if query.Has("restore") {
+			return "RestoreObject"
+		}
+		if query.Has("select") {
+			return "SelectObjectContent"
+		}
+	case http.MethodDelete:
+		if query.Has("uploadId") {
+			return "AbortMultipartUpload"
+		}
+		return "DeleteObject"
+	}
+	return ""
+}
+
+var fakeMethods = map[string]string{
+	http.MethodGet:    "Get",
+	http.MethodPut:    "Put",
+	http.MethodDelete: "Delete",
+}
+
+// fakeSubresources is the API names of bucket subresources stored as configs.
+var fakeSubresources = map[string]string{
+	"versioning":   "Versioning",
+	"encryption":   "Encryption",
+	"object-lock":  "ObjectLockConfig",
+	"tagging":      "Tagging",
+	"lifecycle":    "Lifecycle",
+	"policy":       "Policy",
+	"replication":  "Replication",
+	"notification": "Notification",
+}
+
+// fakeConfigNotFound is the error codes while subresources are not configured.
+var fakeConfigNotFound = map[string]string{
+	"versioning":  "NoSuchVersioningConfiguration",
+	"encryption":  "ServerSideEncryptionConfigurationNotFoundError",
+	"object-lock": "ObjectLockConfigurationNotFoundError",
+	"tagging":     "NoSuchTagSet",
+	"lifecycle":   "NoSuchLifecycleConfiguration",
+	"policy":      "NoSuchBucketPolicy",
+	"replication": "ReplicationConfigurationNotFoundError",
+}
+
+func fakeSubresource(query url.Values) string {
+	for k := range fakeSubresources {
+		if query.Has(k) {
+			return k
+		}
+	}
+	return ""
+}
+
+type fakeListEntry struct {
+	Key          string
+	LastModified string
+	ETag         string
+	Size         int64
+	StorageClass string
+	Owner        *fakeOwner    `xml:",omitempty"`
+	UserMetadata *fakeMetadata `xml:",omitempty"`
+}
+
+type fakeOwner struct {
+	ID          string
+	DisplayName string
+}
+
+type fakeMetadata struct {
+	Items []fakeMetadataItem
+}
+
+type fakeMetadataItem struct {
+	XMLName xml.Name
+	Value   string `xml:",chardata"`
+}
+
+func (f *fakeServer) listObjectsV2(w http.ResponseWriter, bucket string, query url.Values) {
+	prefix, delimiter := query.Get("prefix"), query.Get("delimiter")
+	maxKeys := 1000
+	if v := query.Get("max-keys"); v != "" {
+		maxKeys, _ = strconv.Atoi(v)
+	}
+
+	// Entries are listed after the continuation token, which is the last key
+	// of the previous page, so that common prefixes will not be returned twice.
+	// While listing after start-after, keys under the common prefix which is
+	// equal to start-after will be rolled up into it again, the same as S3.
+	after, afterToken := query.Get("start-after"), false
+	if token := query.Get("continuation-token"); token != "" {
+		v, err := base64.StdEncoding.DecodeString(token)
+		if err != nil {
+			writeFakeError(w, http.StatusBadRequest, "InvalidArgument")
+			return
+		}
+		after, afterToken = string(v), true
+	}
+
+	var result struct {
+		XMLName               xml.Name `xml:"ListBucketResult"`
+		Name                  string
+		Prefix                string
+		Delimiter             string
+		MaxKeys               int
+		IsTruncated           bool
+		NextContinuationToken string `xml:",omitempty"`
+		Contents              []fakeListEntry
+		CommonPrefixes        []struct{ Prefix string }
+	}
+	result.Name, result.Prefix, result.Delimiter, result.MaxKeys = bucket, prefix, delimiter, maxKeys
+
+	var count int
+	var last string
+	for _, k := range f.keysLocked(bucket) {
+		if !strings.HasPrefix(k, prefix) || k <= after {
+			continue
+		}
+		entry := k
+		if i := strings.Index(k[len(prefix):], delimiter); delimiter != "" && i >= 0 {
+			entry = k[:len(prefix)+i+len(delimiter)]
+			if entry == last || (afterToken && entry <= after) {
+				continue
+			}
+		}
+		if count == maxKeys {
+			result.IsTruncated = true
+			result.NextContinuationToken = base64.StdEncoding.EncodeToString([]byte(last))
+			break
+		}
+		count++
+		last = entry
+
+		if entry != k {
+			result.CommonPrefixes = append(result.CommonPrefixes, struct{ Prefix string }{entry})
+			continue
+		}
+		o := f.objects[bucket][k]
+		v := fakeListEntry{
+			Key:          k,
+			LastModified: o.lastModified.Format("2006-01-02T15:04:05.000Z"),
+			ETag:         `"` + o.etag + `"`,
+			Size:         int64(len(o.content)),
+			StorageClass: o.storageClass,
+		}
+		if v.StorageClass == "" {
+			v.StorageClass = "STANDARD"
+		}
+		if query.Get("fetch-owner") == "true" && o.owner != "" {
+			v.Owner = &fakeOwner{ID: o.owner, DisplayName: o.owner}
+		}
+		if query.Get("metadata") == "true" {
+			v.UserMetadata = &fakeMetadata{}
+			if o.contentType != "" {
+				v.UserMetadata.Items = append(v.UserMetadata.Items, fakeMetadataItem{
+					XMLName: xml.Name{Local: "content-type"},
+					Value:   o.contentType,
+				})
+			}
+			for mk, mv := range o.userMetadata {
+				v.UserMetadata.Items = append(v.UserMetadata.Items, fakeMetadataItem{
+					XMLName: xml.Name{Local: "X-Amz-Meta-" + mk},
+					Value:   mv,
+				})
+			}
+			if o.replicationStatus != "" {
+				v.UserMetadata.Items = append(v.UserMetadata.Items, fakeMetadataItem{
+					XMLName: xml.Name{Local: "X-Amz-Replication-Status"},
+					Value:   o.replicationStatus,
+				})
+			}
+		}
+		result.Contents = append(result.Contents, v)
+	}
+	writeXML(w, http.StatusOK, result)
+}
+
+func (f *fakeServer) listMultipartUploads(w http.ResponseWriter, bucket string, query url.Values) {
+	prefix := query.Get("prefix")
+	keyMarker, uploadIDMarker := query.Get("key-marker"), query.Get("upload-id-marker")
+	maxUploads := 1000
+	if v := query.Get("max-uploads"); v != "" {
+		maxUploads, _ = strconv.Atoi(v)
+	}
+
+	var result struct {
+		XMLName            xml.Name `xml:"ListMultipartUploadsResult"`
+		Bucket             string
+		Prefix             string
+		MaxUploads         int
+		IsTruncated        bool
+		NextKeyMarker      string
+		NextUploadIDMarker string       `xml:"NextUploadIdMarker"`
+		Uploads            []fakeUpload `xml:"Upload"`
+	}
+	result.Bucket, result.Prefix, result.MaxUploads = bucket, prefix, maxUploads
+
+	// Uploads of the key marker are listed after the upload id marker, and
+	// all uploads of it are skipped without the upload id marker.
+	var passed bool
+	for _, v := range f.uploads[bucket] {
+		if !strings.HasPrefix(v.Key, prefix) || v.Key < keyMarker {
+			continue
+		}
+		if v.Key == keyMarker && (uploadIDMarker == "" || !passed) {
+			passed = v.UploadID == uploadIDMarker
+			continue
+		}
+		if len(result.Uploads) == maxUploads {
+			result.IsTruncated = true
+			break
+		}
+		result.Uploads = append(result.Uploads, v)
+		result.NextKeyMarker, result.NextUploadIDMarker = v.Key, v.UploadID
+	}
+	writeXML(w, http.StatusOK, result)
+}
+
+func (f *fakeServer) listenBucketNotification(w http.ResponseWriter) {
+	if len(f.notifications) == 0 {
+		return
+	}
+	i := f.requests["ListenBucketNotification"] - 1
+	if i >= len(f.notifications)-1 {
+		for _, v := range f.notifications[len(f.notifications)-1] {
+			w.Write([]byte(v + "\n"))
+		}
+		return
+	}
+	for _, v := range f.notifications[i] {
+		w.Write([]byte(v + "\n"))
+	}
+	// Drop the connection after the lines are received, like the connection
+	// is lost, which will be recovered by the retry of Watch.
+	w.(http.Flusher).Flush()
+	panic(http.ErrAbortHandler)
+}
+
+// selectObjectContent returns the content of the object as the results
+// without running the expression, followed by the stats.
+func (f *fakeServer) selectObjectContent(w http.ResponseWriter, bucket, key string) {
+	o, ok := f.objects[bucket][key]
+	if !ok {
+		writeFakeError(w, http.StatusNotFound, "NoSuchKey")
+		return
+	}
+	n := len(o.content)
+	stats := fmt.Sprintf("<Stats><BytesScanned>%d</BytesScanned><BytesProcessed>%d</BytesProcessed><BytesReturned>%d</BytesReturned></Stats>", n, 2*n, n)
+	w.Write(fakeEventMessage("Records", "application/octet-stream", o.content))
+	w.Write(fakeEventMessage("Stats", "text/xml", []byte(stats)))
+	w.Write(fakeEventMessage("End", "", nil))
+}
+
+// fakeEventMessage encodes an event message of the event stream.
+func fakeEventMessage(eventType, contentType string, payload []byte) []byte {
+	var headers bytes.Buffer
+	for _, v := range [][2]string{{":message-type", "event"}, {":event-type", eventType}, {":content-type", contentType}} {
+		if v[1] == "" {
+			continue
+		}
+		// The value type 7 is string.
+		headers.WriteByte(byte(len(v[0])))
+		headers.WriteString(v[0])
+		headers.WriteByte(7)
+		binary.Write(&headers, binary.BigEndian, uint16(len(v[1])))
+		headers.WriteString(v[1])
+	}
+
+	var m bytes.Buffer
+	// The prelude is the total length and the headers length with its CRC,
+	// and the message ends with the CRC of all bytes before.
+	binary.Write(&m, binary.BigEndian, uint32(12+headers.Len()+len(payload)+4))
+	binary.Write(&m, binary.BigEndian, uint32(headers.Len()))
+	binary.Write(&m, binary.BigEndian, crc32.ChecksumIEEE(m.Bytes()))
+	m.Write(headers.Bytes())
+	m.Write(payload)
+	binary.Write(&m, binary.BigEndian, crc32.ChecksumIEEE(m.Bytes()))
+	return m.Bytes()
+}
+
+func (f *fakeServer) abortMultipartUpload(w http.ResponseWriter, bucket, key, uploadID string) {
+	for i, v := range f.uploads[bucket] {
+		if v.Key == key && v.UploadID == uploadID {
+			f.uploads[bucket] = append(f.uploads[bucket][:i], f.uploads[bucket][i+1:]...)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+	}
+	writeFakeError(w, http.StatusNotFound, "NoSuchUpload")
+}
+
+func (f *fakeServer) headObject(w http.ResponseWriter, r *http.Request, bucket, key string, withContent bool) {
+	o, ok := f.objects[bucket][key]
+	if !ok {
+		writeFakeError(w, http.StatusNotFound, "NoSuchKey")
+		return
+	}
+	switch fakePrecondition(r.Header, "", o, http.StatusNotModified) {
+	case http.StatusNotModified:
+		w.WriteHeader(http.StatusNotModified)
+		return
+	case http.StatusPreconditionFailed:
+		writeFakeError(w, http.StatusPreconditionFailed, "PreconditionFailed")
+		return
+	}
+	h := w.Header()
+	h.Set("Content-Length", strconv.Itoa(len(o.content)))
+	h.Set("ETag", `"`+o.etag+`"`)
+	h.Set("Last-Modified", o.lastModified.Format(http.TimeFormat))
+	if o.contentType != "" {
+		h.Set("Content-Type", o.contentType)
+	}
+	for k, v := range o.userMetadata {
+		h.Set("X-Amz-Meta-"+k, v)
+	}
+	if o.replicationStatus != "" {
+		h.Set("X-Amz-Replication-Status", o.replicationStatus)
+	}
+	if o.storageClass != "" {
+		h.Set("X-Amz-Storage-Class", o.storageClass)
+	}
+	for k, v := range o.headers {
+		h.Set(k, v)
+	}
+	w.WriteHeader(http.StatusOK)
+	if withContent {
+		w.Write(o.content)
+	}
+}
+
+func (f *fakeServer) putObject(w http.ResponseWriter, r *http.Request, bucket, key string) {
+	var content []byte
+	var err error
+	if strings.HasPrefix(r.Header.Get("X-Amz-Content-Sha256"), "STREAMING-") {
+		content, err = readAWSChunked(r.Body)
+	} else {
+		content, err = io.ReadAll(r.Body)
+	}
+	if err != nil {
+		writeFakeError(w, http.StatusBadRequest, "IncompleteBody")
+		return
+	}
+	if fakePrecondition(r.Header, "", f.objects[bucket][key], http.StatusPreconditionFailed) != 0 {
+		writeFakeError(w, http.StatusPreconditionFailed, "PreconditionFailed")
+		return
+	}
+
+	o := &fakeObject{
+		content:      content,
+		lastModified: time.Now().UTC().Truncate(time.Second),
+		contentType:  r.Header.Get("Content-Type"),
+		userMetadata: make(map[string]string),
+	}
+	for k, v := range r.Header {
+		if strings.HasPrefix(k, "X-Amz-Meta-") {
+			o.userMetadata[strings.TrimPrefix(k, "X-Amz-Meta-")] = v[0]
+		}
+	}
+	f.putLocked(bucket, key, o)
+	w.Header().Set("ETag", `"`+o.etag+`"`)
+	w.WriteHeader(http.StatusOK)
+}
+
+func (f *fakeServer) copyObject(w http.ResponseWriter, r *http.Request, bucket, key string) {
+	source, err := url.PathUnescape(r.Header.Get("X-Amz-Copy-Source"))
+	if err != nil {
+		writeFakeError(w, http.StatusBadRequest, "InvalidArgument")
+		return
+	}
+	source = strings.TrimPrefix(source, "/")
+	i := strings.Index(source, "/")
+	if i < 0 {
+		writeFakeError(w, http.StatusBadRequest, "InvalidArgument")
+		return
+	}
+	src, ok := f.objects[source[:i]][source[i+1:]]
+	if !ok {
+		writeFakeError(w, http.StatusNotFound, "NoSuchKey")
+		return
+	}
+	if fakePrecondition(r.Header, "X-Amz-Copy-Source-", src, http.StatusPreconditionFailed) != 0 {
+		writeFakeError(w, http.StatusPreconditionFailed, "PreconditionFailed")
+		return
+	}
+
+	o := *src
+	o.lastModified = time.Now().UTC().Truncate(time.Second)
+	f.putLocked(bucket, key, &o)
+	writeXML(w, http.StatusOK, struct {
+		XMLName      xml.Name `xml:"CopyObjectResult"`
+		ETag         string
+		LastModified string
+	}{
+		ETag:         `"` + o.etag + `"`,
+		LastModified: o.lastModified.Format("2006-01-02T15:04:05.000Z"),
+	})
+}
+
+// fakePrecondition checks conditional headers with the prefix against o, which
+// is nil if the object doesn't exist, and returns the status code of unmet
+// conditions or 0. Unmet if-none-match and if-modified-since are responded
+// with notModified.
+func fakePrecondition(h http.Header, prefix string, o *fakeObject, notModified int) int {
+	if v := h.Get(prefix + "If-Match"); v != "" && (o == nil || !fakeEtagMatch(v, o.etag)) {
+		return http.StatusPreconditionFailed
+	}
+	if v := h.Get(prefix + "If-Unmodified-Since"); v != "" && o != nil {
+		t, err := http.ParseTime(v)
+		if err == nil && o.lastModified.After(t) {
+			return http.StatusPreconditionFailed
+		}
+	}
+	if v := h.Get(prefix + "If-None-Match"); v != "" && o != nil && fakeEtagMatch(v, o.etag) {
+		return notModified
+	}
+	if v := h.Get(prefix + "If-Modified-Since"); v != "" && o != nil {
+		t, err := http.ParseTime(v)
+		if err == nil && !o.lastModified.After(t) {
+			return notModified
+		}
+	}
+	return 0
+}
+
+func fakeEtagMatch(v, etag string) bool {
+	return v == "*" || strings.Trim(v, `"`) == etag
+}
+
+// readAWSChunked decodes the body signed via STREAMING-AWS4-HMAC-SHA256-PAYLOAD,
+// which is sent by minio-go over plain HTTP.
+func readAWSChunked(r io.Reader) ([]byte, error) {
+	br := bufio.NewReader(r)
+	var buf bytes.Buffer
+	for {
+		line, err := br.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		size, err := strconv.ParseInt(strings.SplitN(strings.TrimSpace(line), ";", 2)[0], 16, 64)
+		if err != nil {
+			return nil, err
+		}
+		if size == 0 {
+			return buf.Bytes(), nil
+		}
+		if _, err = io.CopyN(&buf, br, size); err != nil {
+			return nil, err
+		}
+		if _, err = br.Discard(2); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func writeXML(w http.ResponseWriter, status int, v interface{}) {
+	content, err := xml.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	w.Write(content)
+}
+
+func writeFakeError(w http.ResponseWriter, status int, code string) {
+	writeXML(w, status, struct {
+		XMLName xml.Name `xml:"Error"`
+		Code    string
+		Message string
+	}{Code: code, Message: fmt.Sprintf("fake server: %s", code)})
+}
+
+// listAll lists all objects under path and returns their paths.
+func listAll(t *testing.T, store *Storage, path string, pairs ...types.Pair) (paths []string) {
+	it, err := store.List(path, pairs...)
+	if err != nil {
+		t.Fatalf("list %s: %v", path, err)
+	}
+	for {
+		o, err := it.Next()
+		if err == types.IterateDone {
+			return paths
+		}
+		if err != nil {
+			t.Fatalf("list %s: %v", path, err)
+		}
+		paths = append(paths, o.Path)
+	}
+}
@@ -2,6 +2,7 @@ package minio
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/minio/minio-go/v7"
 
@@ -9,15 +10,57 @@ import (
 	. "github.com/beyondstorage/go-storage/v4/types"
 )
 
+// configureBucket applies configurations given by pairs to the created bucket.
+func (s *Service) configureBucket(ctx context.Context, name string, opt pairServiceCreate) (err error) {
+	if opt.HasVersioning {
+		err = s.setVersioning(ctx, name, opt.Versioning)
+		if err != nil {
+			return err
+		}
+	}
+	if opt.HasEncryption {
+		err = s.setEncryption(ctx, name, opt.Encryption)
+		if err != nil {
+			return err
+		}
+	}
+	if opt.HasBucketTags {
+		err = s.setTags(ctx, name, opt.BucketTags)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (s *Service) create(ctx context.Context, name string, opt pairServiceCreate) (store Storager, err error) {
-	st, err := s.newStorage(ps.WithName(name))
+	pairs := []Pair{ps.WithName(name)}
+	if opt.HasLocation {
+		pairs = append(pairs, ps.WithLocation(opt.Location))
+	}
+	st, err := s.newStorage(pairs...)
 	if err != nil {
 		return nil, err
 	}
-	err = s.service.MakeBucket(ctx, name, minio.MakeBucketOptions{})
+	options := minio.MakeBucketOptions{}
+	if opt.HasLocation {
+		options.Region = opt.Location
+	}
+	err = s.service.MakeBucket(ctx, name, options)
 	if err != nil {
 		return nil, err
 	}
+
+	err = s.configureBucket(ctx, name, opt)
+	if err != nil {
+		// Remove the half configured bucket so that create could be retried.
+		// The bucket is empty, and should be removed even if ctx is canceled.
+		rerr := s.service.RemoveBucket(context.WithoutCancel(ctx), name)
+		if rerr != nil {
+			return nil, fmt.Errorf("%w, and the bucket is left half configured: %v", err, rerr)
+		}
+		return nil, err
+	}
 	return st, nil
 }
 
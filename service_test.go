@@ -0,0 +1,50 @@
+package minio
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/beyondstorage/go-storage/v4/services"
+)
+
+func TestCreateRemovesHalfConfiguredBucket(t *testing.T) {
+	f := newFakeServer(t)
+	store := f.newStorage(t, "bucket", "/")
+	srv := &Service{service: store.client}
+
+	f.fail("PutBucketTagging", http.StatusForbidden, "AccessDenied")
+	_, err := srv.Create("new", WithVersioning(VersioningConfig{Status: VersioningEnabled}), WithBucketTags(map[string]string{"k": "v"}))
+	if !errors.Is(err, services.ErrPermissionDenied) {
+		t.Fatalf("create returned %v, expected ErrPermissionDenied", err)
+	}
+	if n := f.count("RemoveBucket"); n != 1 {
+		t.Errorf("create sent %d RemoveBucket requests, expected 1", n)
+	}
+
+	// Create could be retried after the failure.
+	delete(f.failures, "PutBucketTagging")
+	_, err = srv.Create("new", WithVersioning(VersioningConfig{Status: VersioningEnabled}), WithBucketTags(map[string]string{"k": "v"}))
+	if err != nil {
+		t.Fatalf("retry create: %v", err)
+	}
+	if _, ok := f.configs["new?tagging"]; !ok {
+		t.Errorf("tags of the bucket are not set")
+	}
+}
+
+func TestCreateReportsRemoveFailure(t *testing.T) {
+	f := newFakeServer(t)
+	store := f.newStorage(t, "bucket", "/")
+	srv := &Service{service: store.client}
+
+	f.fail("PutBucketEncryption", http.StatusForbidden, "AccessDenied")
+	f.fail("RemoveBucket", http.StatusForbidden, "AccessDenied")
+	_, err := srv.Create("new", WithEncryption(BucketEncryption{Algorithm: EncryptionAlgorithmS3}))
+	if err == nil {
+		t.Fatalf("create should fail")
+	}
+	if !errors.Is(err, services.ErrUnexpected) {
+		t.Errorf("create returned %v, expected ErrUnexpected", err)
+	}
+}
@@ -16,13 +16,44 @@ import (
 	. "github.com/beyondstorage/go-storage/v4/types"
 )
 
-const defaultListObjectBufferSize = 100
+const (
+	defaultListObjectBufferSize = 100
+	// listPageSizeMaximum is the max number of keys returned by one list request.
+	listPageSizeMaximum = 1000
+)
+
+const (
+	// multipartNumberMaximum is the max number of parts in one multipart upload.
+	multipartNumberMaximum = 10000
+	// multipartSizeMaximum is the max size of one part, 5GiB.
+	multipartSizeMaximum = 5 * 1024 * 1024 * 1024
+	// multipartSizeMinimum is the min size of one part except the last one, 5MiB.
+	multipartSizeMinimum = 5 * 1024 * 1024
+	// writeSizeMaximum is the max size of one object, 5TiB.
+	//
+	// Objects larger than 5GiB will be written via multipart upload by minio-go.
+	writeSizeMaximum = 5 * 1024 * 1024 * 1024 * 1024
+	// copySizeMaximum is the max size of the object to copy in one request, 5GiB.
+	copySizeMaximum = 5 * 1024 * 1024 * 1024
+)
 
 func (s *Storage) copy(ctx context.Context, src string, dst string, opt pairStorageCopy) (err error) {
 	srcOpts := minio.CopySrcOptions{
 		Bucket: s.bucket,
 		Object: s.getAbsPath(src),
 	}
+	if opt.HasIfMatch {
+		srcOpts.MatchETag = opt.IfMatch
+	}
+	if opt.HasIfNoneMatch {
+		srcOpts.NoMatchETag = opt.IfNoneMatch
+	}
+	if opt.HasIfModifiedSince {
+		srcOpts.MatchModifiedSince = opt.IfModifiedSince
+	}
+	if opt.HasIfUnmodifiedSince {
+		srcOpts.MatchUnmodifiedSince = opt.IfUnmodifiedSince
+	}
 	dstOpts := minio.CopyDestOptions{
 		Bucket: s.bucket,
 		Object: s.getAbsPath(dst),
@@ -64,56 +95,150 @@ func (s *Storage) delete(ctx context.Context, path string, opt pairStorageDelete
 
 func (s *Storage) list(ctx context.Context, path string, opt pairStorageList) (oi *ObjectIterator, err error) {
 	rp := s.getAbsPath(path)
-	options := minio.ListObjectsOptions{}
+	pageSize := defaultListObjectBufferSize
+	if opt.HasPageSize {
+		// An empty page will be treated as the end of the listing.
+		if opt.PageSize <= 0 {
+			return nil, fmt.Errorf("page_size must be positive, got %d", opt.PageSize)
+		}
+		pageSize = opt.PageSize
+		if pageSize > listPageSizeMaximum {
+			pageSize = listPageSizeMaximum
+		}
+	}
+
+	var delimiter string
 	if !opt.HasListMode || opt.ListMode.IsPrefix() {
-		options.Recursive = true
+		delimiter = ""
 	} else if opt.ListMode.IsDir() {
 		if !strings.HasSuffix(rp, "/") {
 			rp += "/"
 		}
+		delimiter = "/"
+	} else if opt.ListMode.IsPart() {
+		input, err := s.newPartPageStatus(rp, pageSize, opt)
+		if err != nil {
+			return nil, err
+		}
+		return NewObjectIterator(ctx, s.nextPartObjectPage, input), nil
 	} else {
 		return nil, services.ListModeInvalidError{Actual: opt.ListMode}
 	}
-	options.Prefix = rp
+
+	filter, err := newObjectFilter(opt)
+	if err != nil {
+		return nil, err
+	}
+
 	input := &objectPageStatus{
-		bufferSize: defaultListObjectBufferSize,
-		options:    options,
+		maxKeys:   pageSize,
+		prefix:    rp,
+		delimiter: delimiter,
+		filter:    filter,
+	}
+	if opt.HasStartAfter {
+		input.startAfter = s.getAbsPath(opt.StartAfter)
+		input.lastKey = input.startAfter
+	}
+	if opt.HasContinuationToken {
+		input.startAfter = ""
+		input.lastKey = ""
+		input.continuationToken = opt.ContinuationToken
+	}
+	if opt.HasListMetadata {
+		input.withMetadata = opt.ListMetadata
 	}
 	return NewObjectIterator(ctx, s.nextObjectPage, input), nil
 }
 
+// metadata doesn't send any request, so the location is only returned while
+// it's given by the location pair or by creating the bucket. The location,
+// versioning, default encryption and object lock configuration of the bucket
+// are served by BucketInfo instead.
 func (s *Storage) metadata(opt pairStorageMetadata) (meta *StorageMeta) {
 	meta = NewStorageMeta()
 	meta.Name = s.bucket
 	meta.WorkDir = s.workDir
+	if s.location != "" {
+		meta.SetLocation(s.location)
+	}
+	meta.SetMultipartNumberMaximum(multipartNumberMaximum)
+	meta.SetMultipartSizeMaximum(multipartSizeMaximum)
+	meta.SetMultipartSizeMinimum(multipartSizeMinimum)
+	meta.SetWriteSizeMaximum(writeSizeMaximum)
+	meta.SetCopySizeMaximum(copySizeMaximum)
 	return meta
 }
 
 func (s *Storage) nextObjectPage(ctx context.Context, page *ObjectPage) error {
 	input := page.Status.(*objectPageStatus)
-	if input.objChan == nil {
-		input.objChan = s.client.ListObjects(ctx, s.bucket, input.options)
-	}
-	for i := 0; i < input.bufferSize; i++ {
-		v, ok := <-input.objChan
-		if !ok {
-			return IterateDone
-		}
-		if v.Err != nil {
-			return v.Err
-		}
-		o, err := s.formatFileObject(v)
+
+	// Keep fetching while all objects in the page have been filtered out,
+	// because an empty page will be treated as the end of the listing.
+	for {
+		infos, done, err := s.listObjectPage(ctx, input)
 		if err != nil {
 			return err
 		}
-		page.Data = append(page.Data, o)
-		input.counter++
+		for _, v := range infos {
+			o, err := s.formatFileObject(v)
+			if err != nil {
+				return err
+			}
+			if !input.filter.match(o) {
+				continue
+			}
+			page.Data = append(page.Data, o)
+		}
+		if done {
+			return IterateDone
+		}
+		if len(page.Data) > 0 {
+			return nil
+		}
+	}
+}
+
+func (s *Storage) nextPartObjectPage(ctx context.Context, page *ObjectPage) error {
+	input := page.Status.(*partPageStatus)
+
+	core := minio.Core{Client: s.client}
+	result, err := core.ListMultipartUploads(ctx, s.bucket, input.prefix,
+		input.keyMarker, input.uploadIDMarker, "", input.maxUploads)
+	if err != nil {
+		return err
+	}
+	for _, v := range result.Uploads {
+		page.Data = append(page.Data, s.formatPartObject(v))
+	}
+
+	if !result.IsTruncated {
+		input.keyMarker, input.uploadIDMarker = "", ""
+		return IterateDone
 	}
+	input.keyMarker, input.uploadIDMarker = result.NextKeyMarker, result.NextUploadIDMarker
 	return nil
 }
 
 func (s *Storage) reach(ctx context.Context, path string, opt pairStorageReach) (url_ string, err error) {
 	rp := s.getAbsPath(path)
+
+	if opt.HasPublicURL && opt.PublicURL {
+		if opt.HasExpire {
+			return "", fmt.Errorf("expire is not allowed for the unsigned url")
+		}
+		public, err := s.isPublicRead(ctx, rp)
+		if err != nil {
+			return "", err
+		}
+		if !public {
+			return "", fmt.Errorf("object %s is not readable by anonymous users", rp)
+		}
+		u := s.client.EndpointURL()
+		u.Path = "/" + s.bucket + "/" + rp
+		return u.String(), nil
+	}
+
 	var expire = time.Hour * 1
 	if opt.HasExpire {
 		expire = opt.Expire
@@ -127,7 +252,32 @@ func (s *Storage) reach(ctx context.Context, path string, opt pairStorageReach)
 
 func (s *Storage) read(ctx context.Context, path string, w io.Writer, opt pairStorageRead) (n int64, err error) {
 	rp := s.getAbsPath(path)
-	output, err := s.client.GetObject(ctx, s.bucket, rp, minio.GetObjectOptions{})
+	options := minio.GetObjectOptions{}
+	if opt.HasIfMatch {
+		err = options.SetMatchETag(opt.IfMatch)
+		if err != nil {
+			return 0, err
+		}
+	}
+	if opt.HasIfNoneMatch {
+		err = options.SetMatchETagExcept(opt.IfNoneMatch)
+		if err != nil {
+			return 0, err
+		}
+	}
+	if opt.HasIfModifiedSince {
+		err = options.SetModified(opt.IfModifiedSince)
+		if err != nil {
+			return 0, err
+		}
+	}
+	if opt.HasIfUnmodifiedSince {
+		err = options.SetUnmodified(opt.IfUnmodifiedSince)
+		if err != nil {
+			return 0, err
+		}
+	}
+	output, err := s.client.GetObject(ctx, s.bucket, rp, options)
 	if err != nil {
 		return 0, err
 	}
@@ -162,7 +312,35 @@ func (s *Storage) stat(ctx context.Context, path string, opt pairStorageStat) (o
 		}
 		rp += "/"
 	}
-	output, err := s.client.StatObject(ctx, s.bucket, rp, minio.StatObjectOptions{})
+	options := minio.StatObjectOptions{
+		// Ask for checksums so that they can be returned in system metadata.
+		Checksum: true,
+	}
+	if opt.HasIfMatch {
+		err = options.SetMatchETag(opt.IfMatch)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if opt.HasIfNoneMatch {
+		err = options.SetMatchETagExcept(opt.IfNoneMatch)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if opt.HasIfModifiedSince {
+		err = options.SetModified(opt.IfModifiedSince)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if opt.HasIfUnmodifiedSince {
+		err = options.SetUnmodified(opt.IfUnmodifiedSince)
+		if err != nil {
+			return nil, err
+		}
+	}
+	output, err := s.client.StatObject(ctx, s.bucket, rp, options)
 	if err != nil {
 		return nil, err
 	}
@@ -194,6 +372,15 @@ func (s *Storage) write(ctx context.Context, path string, r io.Reader, size int6
 	if opt.HasStorageClass {
 		options.StorageClass = opt.StorageClass
 	}
+	if opt.HasUserMetadata {
+		options.UserMetadata = opt.UserMetadata
+	}
+	if opt.HasIfMatch {
+		options.SetMatchETag(opt.IfMatch)
+	}
+	if opt.HasIfNoneMatch {
+		options.SetMatchETagExcept(opt.IfNoneMatch)
+	}
 	_, err = s.client.PutObject(ctx, s.bucket, rp, r, size, options)
 	if err != nil {
 		return 0, err
@@ -0,0 +1,570 @@
+package minio
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	ps "github.com/beyondstorage/go-storage/v4/pairs"
+	"github.com/beyondstorage/go-storage/v4/services"
+	"github.com/beyondstorage/go-storage/v4/types"
+)
+
+// putDirAndFiles puts n dirs and n files under p/, which are interleaved
+// while sorted, and returns the expected paths of ListModeDir.
+func putDirAndFiles(f *fakeServer, n int) (expected []string) {
+	for i := 0; i < n; i++ {
+		f.put("bucket", fmt.Sprintf("p/%02d-d/a", i), "")
+		f.put("bucket", fmt.Sprintf("p/%02d-d/b", i), "")
+		f.put("bucket", fmt.Sprintf("p/%02d-f", i), "")
+		expected = append(expected, fmt.Sprintf("p/%02d-d", i), fmt.Sprintf("p/%02d-f", i))
+	}
+	return expected
+}
+
+func TestListDirPages(t *testing.T) {
+	f := newFakeServer(t)
+	expected := putDirAndFiles(f, 30)
+	// The marker of the dir itself should be skipped.
+	f.put("bucket", "p/", "")
+	store := f.newStorage(t, "bucket", "/")
+
+	for _, pageSize := range []int{1, 2, 7, 59, 60, 1000} {
+		t.Run(fmt.Sprintf("page size %d", pageSize), func(t *testing.T) {
+			before := f.count("ListObjectsV2")
+			actual := listAll(t, store, "p", ps.WithListMode(types.ListModeDir), WithPageSize(pageSize))
+			if !reflect.DeepEqual(actual, expected) {
+				t.Errorf("list returned %d entries %v, expected %d entries %v", len(actual), actual, len(expected), expected)
+			}
+
+			// Every page should be exactly one request, the marker of the dir
+			// is counted by the server too.
+			pages := (len(expected) + pageSize) / pageSize
+			if n := f.count("ListObjectsV2") - before; n != pages {
+				t.Errorf("list sent %d requests, expected %d", n, pages)
+			}
+		})
+	}
+}
+
+func TestListPrefixPages(t *testing.T) {
+	f := newFakeServer(t)
+	putDirAndFiles(f, 10)
+	store := f.newStorage(t, "bucket", "/p/")
+
+	expected := f.keys("bucket")
+	for i := range expected {
+		expected[i] = expected[i][len("p/"):]
+	}
+	actual := listAll(t, store, "", WithPageSize(3))
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("list returned %v, expected %v", actual, expected)
+	}
+}
+
+func TestListResume(t *testing.T) {
+	f := newFakeServer(t)
+	expected := putDirAndFiles(f, 10)
+	store := f.newStorage(t, "bucket", "/")
+
+	for _, pageSize := range []int{1, 3, 4} {
+		t.Run(fmt.Sprintf("page size %d", pageSize), func(t *testing.T) {
+			var actual []string
+			var token string
+			// Start a new listing after every page, like a job that crashed
+			// and resumed from the saved continuation token.
+			for i := 0; ; i++ {
+				pairs := []types.Pair{ps.WithListMode(types.ListModeDir), WithPageSize(pageSize)}
+				if i > 0 {
+					if token == "" {
+						break
+					}
+					pairs = append(pairs, ps.WithContinuationToken(token))
+				}
+				it, err := store.List("p/", pairs...)
+				if err != nil {
+					t.Fatalf("list: %v", err)
+				}
+				for j := 0; j < pageSize; j++ {
+					o, err := it.Next()
+					if err == types.IterateDone {
+						break
+					}
+					if err != nil {
+						t.Fatalf("list: %v", err)
+					}
+					actual = append(actual, o.Path)
+				}
+				token = it.ContinuationToken()
+			}
+			if !reflect.DeepEqual(actual, expected) {
+				t.Errorf("resumed list returned %v, expected %v", actual, expected)
+			}
+		})
+	}
+}
+
+func TestListStartAfter(t *testing.T) {
+	f := newFakeServer(t)
+	expected := putDirAndFiles(f, 5)
+	store := f.newStorage(t, "bucket", "/")
+
+	// Paging via start_after with the path of the last entry, like a UI does.
+	// Pages ending with dirs must not return them again in the next page.
+	for _, pageSize := range []int{1, 2, 3} {
+		var actual []string
+		var startAfter string
+		for {
+			pairs := []types.Pair{ps.WithListMode(types.ListModeDir), WithPageSize(pageSize)}
+			if startAfter != "" {
+				pairs = append(pairs, WithStartAfter(startAfter))
+			}
+			it, err := store.List("p/", pairs...)
+			if err != nil {
+				t.Fatalf("list: %v", err)
+			}
+			var n int
+			for ; n < pageSize; n++ {
+				o, err := it.Next()
+				if err == types.IterateDone {
+					break
+				}
+				if err != nil {
+					t.Fatalf("list: %v", err)
+				}
+				actual = append(actual, o.Path)
+				startAfter = o.Path
+			}
+			// Stop early if dirs are returned again and again.
+			if n < pageSize || len(actual) > len(expected) {
+				break
+			}
+		}
+		if !reflect.DeepEqual(actual, expected) {
+			t.Errorf("list with page size %d via start_after returned %v, expected %v", pageSize, actual, expected)
+		}
+	}
+
+	for i, startAfter := range expected {
+		rest := append([]string(nil), expected[i+1:]...)
+		actual := listAll(t, store, "p/", ps.WithListMode(types.ListModeDir), WithStartAfter(startAfter), WithPageSize(2))
+		if !reflect.DeepEqual(actual, rest) {
+			t.Errorf("list after %s returned %v, expected %v", startAfter, actual, rest)
+		}
+	}
+}
+
+func TestListMetadata(t *testing.T) {
+	f := newFakeServer(t)
+	for i := 0; i < 5; i++ {
+		o := f.put("bucket", fmt.Sprintf("p/%02d", i), "")
+		o.contentType = "text/plain"
+		o.userMetadata = map[string]string{"Index": fmt.Sprint(i)}
+	}
+	store := f.newStorage(t, "bucket", "/")
+
+	check := func(it *types.ObjectIterator, from int) {
+		t.Helper()
+		for i := from; ; i++ {
+			o, err := it.Next()
+			if err == types.IterateDone {
+				if i != 5 {
+					t.Errorf("list returned %d objects, expected %d", i-from, 5-from)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("list: %v", err)
+			}
+			if ct := o.MustGetContentType(); ct != "text/plain" {
+				t.Errorf("%s has content type %q", o.Path, ct)
+			}
+			if um := o.MustGetUserMetadata(); um["Index"] != fmt.Sprint(i) {
+				t.Errorf("%s has user metadata %v", o.Path, um)
+			}
+		}
+	}
+
+	it, err := store.List("p/", WithListMetadata(), WithPageSize(2))
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err = it.Next(); err != nil {
+			t.Fatalf("list: %v", err)
+		}
+	}
+	check(it, 2)
+
+	// Resume from the first page.
+	it, err = store.List("p/", WithListMetadata(), WithPageSize(2))
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err = it.Next(); err != nil {
+			t.Fatalf("list: %v", err)
+		}
+	}
+	it, err = store.List("p/", WithListMetadata(), WithPageSize(2), ps.WithContinuationToken(it.ContinuationToken()))
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	check(it, 2)
+}
+
+func TestListFilterSkipsEmptyPages(t *testing.T) {
+	f := newFakeServer(t)
+	var expected []string
+	for i := 0; i < 20; i++ {
+		f.put("bucket", fmt.Sprintf("p/%02d.log", i), "")
+		if i%7 == 6 {
+			f.put("bucket", fmt.Sprintf("p/%02d.txt", i), "")
+			expected = append(expected, fmt.Sprintf("p/%02d.txt", i))
+		}
+	}
+	store := f.newStorage(t, "bucket", "/")
+
+	actual := listAll(t, store, "p/", WithGlob("p/*.txt"), WithPageSize(3))
+	sort.Strings(actual)
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("list returned %v, expected %v", actual, expected)
+	}
+}
+
+func TestListPageSize(t *testing.T) {
+	f := newFakeServer(t)
+	putDirAndFiles(f, 3)
+	store := f.newStorage(t, "bucket", "/")
+
+	for _, pageSize := range []int{0, -1} {
+		_, err := store.List("p/", WithPageSize(pageSize))
+		if err == nil {
+			t.Errorf("list with page size %d should fail", pageSize)
+		}
+	}
+
+	listAll(t, store, "p/", WithPageSize(5000))
+	if v := f.query("ListObjectsV2").Get("max-keys"); v != "1000" {
+		t.Errorf("list with page size 5000 sent max-keys %s, expected 1000", v)
+	}
+}
+
+func TestListCancel(t *testing.T) {
+	f := newFakeServer(t)
+	f.put("bucket", "a", "a")
+	f.stall(t, "ListObjectsV2")
+	store := f.newStorage(t, "bucket", "/")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	it, err := store.ListWithContext(ctx, "", ps.WithListMode(types.ListModePrefix))
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	time.AfterFunc(100*time.Millisecond, cancel)
+
+	// The request in flight never returns, but the iteration should stop.
+	_, err = it.Next()
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("next after cancel: %v, expected %v", err, context.Canceled)
+	}
+}
+
+func TestListParts(t *testing.T) {
+	f := newFakeServer(t)
+	initiated := time.Now().Add(-time.Hour)
+	var expected []string
+	for i, key := range []string{"p/a", "p/b", "p/b", "p/b", "p/c", "p/d/e", "p/f"} {
+		id := fmt.Sprintf("upload-%d", i)
+		f.initiate("bucket", key, id, initiated)
+		expected = append(expected, key+"#"+id)
+	}
+	f.initiate("bucket", "q/a", "upload-q", initiated)
+	store := f.newStorage(t, "bucket", "/")
+
+	for _, pageSize := range []int{1, 2, 3, 1000} {
+		t.Run(fmt.Sprintf("page size %d", pageSize), func(t *testing.T) {
+			var actual []string
+			var token string
+			// Resume from the continuation token after every page.
+			for i := 0; i == 0 || token != ""; i++ {
+				pairs := []types.Pair{ps.WithListMode(types.ListModePart), WithPageSize(pageSize)}
+				if token != "" {
+					pairs = append(pairs, ps.WithContinuationToken(token))
+				}
+				it, err := store.List("p/", pairs...)
+				if err != nil {
+					t.Fatalf("list: %v", err)
+				}
+				for j := 0; j < pageSize; j++ {
+					o, err := it.Next()
+					if err == types.IterateDone {
+						break
+					}
+					if err != nil {
+						t.Fatalf("list: %v", err)
+					}
+					if !o.Mode.IsPart() {
+						t.Errorf("%s has mode %s", o.Path, o.Mode)
+					}
+					if _, ok := o.GetContentLength(); ok {
+						t.Errorf("%s has content length set", o.Path)
+					}
+					actual = append(actual, o.Path+"#"+o.MustGetMultipartID())
+				}
+				token = it.ContinuationToken()
+				if len(actual) > len(expected) {
+					break
+				}
+			}
+			if !reflect.DeepEqual(actual, expected) {
+				t.Errorf("list returned %v, expected %v", actual, expected)
+			}
+		})
+	}
+
+	it, err := store.List("p/", ps.WithListMode(types.ListModePart), WithStartAfter("p/b"))
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	o, err := it.Next()
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if o.Path != "p/c" {
+		t.Errorf("list after p/b started with %s, expected p/c", o.Path)
+	}
+
+	for _, pair := range []types.Pair{WithListMetadata(), WithGlob("*"), WithMinSize(1), WithModifiedAfter(initiated)} {
+		_, err = store.List("p/", ps.WithListMode(types.ListModePart), pair)
+		var e services.PairUnsupportedError
+		if !errors.As(err, &e) {
+			t.Errorf("list parts with %s returned %v, expected PairUnsupportedError", pair.Key, err)
+		}
+	}
+}
+
+func TestAbortIncompleteUploads(t *testing.T) {
+	f := newFakeServer(t)
+	f.initiate("bucket", "p/old", "upload-old", time.Now().Add(-48*time.Hour))
+	f.initiate("bucket", "p/new", "upload-new", time.Now())
+	f.initiate("bucket", "q/old", "upload-q", time.Now().Add(-48*time.Hour))
+	store := f.newStorage(t, "bucket", "/")
+
+	n, err := store.AbortIncompleteUploads("p/", 24*time.Hour)
+	if err != nil {
+		t.Fatalf("abort: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("aborted %d uploads, expected 1", n)
+	}
+	actual := listAll(t, store, "", ps.WithListMode(types.ListModePart))
+	expected := []string{"p/new", "q/old"}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("uploads %v are left, expected %v", actual, expected)
+	}
+}
+
+// conditionalCases returns pairs of conditions against the object with etag
+// and the last modified time, and whether they are met.
+func conditionalCases(etag string, lastModified time.Time) []struct {
+	pair types.Pair
+	met  bool
+} {
+	return []struct {
+		pair types.Pair
+		met  bool
+	}{
+		{WithIfMatch(etag), true},
+		{WithIfMatch("0123456789abcdef0123456789abcdef"), false},
+		{WithIfNoneMatch("0123456789abcdef0123456789abcdef"), true},
+		{WithIfNoneMatch(etag), false},
+		{WithIfModifiedSince(lastModified.Add(-time.Hour)), true},
+		{WithIfModifiedSince(lastModified.Add(time.Hour)), false},
+		{WithIfUnmodifiedSince(lastModified.Add(time.Hour)), true},
+		{WithIfUnmodifiedSince(lastModified.Add(-time.Hour)), false},
+	}
+}
+
+func TestConditionalReadAndStat(t *testing.T) {
+	f := newFakeServer(t)
+	o := f.put("bucket", "a", "content")
+	lastModified := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+	o.lastModified = lastModified
+	store := f.newStorage(t, "bucket", "/")
+
+	for _, tc := range conditionalCases(o.etag, lastModified) {
+		var buf bytes.Buffer
+		_, err := store.Read("a", &buf, tc.pair)
+		if tc.met && (err != nil || buf.String() != "content") {
+			t.Errorf("read with %s %v returned %q, %v", tc.pair.Key, tc.pair.Value, buf.String(), err)
+		}
+		if !tc.met && !errors.Is(err, ErrPreconditionFailed) {
+			t.Errorf("read with %s %v returned %v, expected ErrPreconditionFailed", tc.pair.Key, tc.pair.Value, err)
+		}
+
+		_, err = store.Stat("a", tc.pair)
+		if tc.met && err != nil {
+			t.Errorf("stat with %s %v: %v", tc.pair.Key, tc.pair.Value, err)
+		}
+		if !tc.met && !errors.Is(err, ErrPreconditionFailed) {
+			t.Errorf("stat with %s %v returned %v, expected ErrPreconditionFailed", tc.pair.Key, tc.pair.Value, err)
+		}
+	}
+}
+
+func TestConditionalCopy(t *testing.T) {
+	f := newFakeServer(t)
+	o := f.put("bucket", "a", "content")
+	lastModified := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+	o.lastModified = lastModified
+	store := f.newStorage(t, "bucket", "/")
+
+	headers := map[string]string{
+		"if_match":            "X-Amz-Copy-Source-If-Match",
+		"if_none_match":       "X-Amz-Copy-Source-If-None-Match",
+		"if_modified_since":   "X-Amz-Copy-Source-If-Modified-Since",
+		"if_unmodified_since": "X-Amz-Copy-Source-If-Unmodified-Since",
+	}
+	for _, tc := range conditionalCases(o.etag, lastModified) {
+		f.mu.Lock()
+		delete(f.objects["bucket"], "b")
+		f.mu.Unlock()
+
+		err := store.Copy("a", "b", tc.pair)
+		if tc.met && err != nil {
+			t.Errorf("copy with %s %v: %v", tc.pair.Key, tc.pair.Value, err)
+		}
+		if !tc.met && !errors.Is(err, ErrPreconditionFailed) {
+			t.Errorf("copy with %s %v returned %v, expected ErrPreconditionFailed", tc.pair.Key, tc.pair.Value, err)
+		}
+		if _, ok := f.get("bucket", "b"); ok != tc.met {
+			t.Errorf("copy with %s %v wrote the object %v, expected %v", tc.pair.Key, tc.pair.Value, ok, tc.met)
+		}
+		if v := f.headers["CopyObject"].Get(headers[tc.pair.Key]); v == "" {
+			t.Errorf("copy with %s didn't send %s", tc.pair.Key, headers[tc.pair.Key])
+		}
+	}
+}
+
+func TestConditionalWrite(t *testing.T) {
+	f := newFakeServer(t)
+	store := f.newStorage(t, "bucket", "/")
+
+	// Create-only writes could be used to elect a leader.
+	_, err := store.Write("leader", strings.NewReader("a"), 1, WithIfNoneMatch("*"))
+	if err != nil {
+		t.Fatalf("create-only write: %v", err)
+	}
+	if v := f.headers["PutObject"].Get("If-None-Match"); v != "*" {
+		t.Errorf("create-only write sent If-None-Match %q, expected *", v)
+	}
+	_, err = store.Write("leader", strings.NewReader("b"), 1, WithIfNoneMatch("*"))
+	if !errors.Is(err, ErrPreconditionFailed) {
+		t.Errorf("create-only write of an existing object returned %v, expected ErrPreconditionFailed", err)
+	}
+
+	o, err := store.Stat("leader")
+	if err != nil {
+		t.Fatalf("stat: %v", err)
+	}
+	etag := strings.Trim(o.MustGetEtag(), `"`)
+	_, err = store.Write("leader", strings.NewReader("c"), 1, WithIfMatch(etag))
+	if err != nil {
+		t.Fatalf("write with the current etag: %v", err)
+	}
+	// The etag has been changed by the previous write.
+	_, err = store.Write("leader", strings.NewReader("d"), 1, WithIfMatch(etag))
+	if !errors.Is(err, ErrPreconditionFailed) {
+		t.Errorf("write with a stale etag returned %v, expected ErrPreconditionFailed", err)
+	}
+	if o, _ := f.get("bucket", "leader"); string(o.content) != "c" {
+		t.Errorf("object has content %q, expected c", o.content)
+	}
+}
+
+func TestStatSystemMetadata(t *testing.T) {
+	f := newFakeServer(t)
+	o := f.put("bucket", "a", "content")
+	o.storageClass = "STANDARD_IA"
+	o.replicationStatus = "COMPLETED"
+	o.headers = map[string]string{
+		"X-Amz-Checksum-Crc32":         "crc32",
+		"X-Amz-Checksum-Crc32c":        "crc32c",
+		"X-Amz-Checksum-Sha1":          "sha1",
+		"X-Amz-Checksum-Sha256":        "sha256",
+		"X-Amz-Expiration":             `expiry-date="Fri, 01 Jan 2021 00:00:00 GMT", rule-id="expire"`,
+		"X-Amz-Restore":                `ongoing-request="false", expiry-date="Sat, 02 Jan 2021 00:00:00 GMT"`,
+		"X-Amz-Server-Side-Encryption": "AES256",
+		"X-Amz-Tagging-Count":          "2",
+		"X-Amz-Version-Id":             "v1",
+	}
+	store := f.newStorage(t, "bucket", "/")
+
+	so, err := store.Stat("a")
+	if err != nil {
+		t.Fatalf("stat: %v", err)
+	}
+	sm := GetObjectSystemMetadata(so)
+	expiration := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	if !sm.Expiration.Equal(expiration) {
+		t.Errorf("expiration is %v, expected %v", sm.Expiration, expiration)
+	}
+	restoreExpiryTime := time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC)
+	if !sm.RestoreExpiryTime.Equal(restoreExpiryTime) {
+		t.Errorf("restore expiry time is %v, expected %v", sm.RestoreExpiryTime, restoreExpiryTime)
+	}
+	sm.Expiration, sm.RestoreExpiryTime = time.Time{}, time.Time{}
+	expected := ObjectSystemMetadata{
+		ChecksumCrc32:        "crc32",
+		ChecksumCrc32c:       "crc32c",
+		ChecksumSha1:         "sha1",
+		ChecksumSha256:       "sha256",
+		ExpirationRuleID:     "expire",
+		ReplicationStatus:    "COMPLETED",
+		ServerSideEncryption: "AES256",
+		StorageClass:         "STANDARD_IA",
+		TagCount:             2,
+		VersionID:            "v1",
+	}
+	if !reflect.DeepEqual(sm, expected) {
+		t.Errorf("system metadata is %+v, expected %+v", sm, expected)
+	}
+
+	// The restore is ongoing without the expiry time.
+	f.mu.Lock()
+	o.headers["X-Amz-Restore"] = `ongoing-request="true"`
+	f.mu.Unlock()
+	so, err = store.Stat("a")
+	if err != nil {
+		t.Fatalf("stat: %v", err)
+	}
+	if sm := GetObjectSystemMetadata(so); !sm.RestoreOngoing || !sm.RestoreExpiryTime.IsZero() {
+		t.Errorf("restore is ongoing %v with expiry time %v, expected ongoing without expiry time", sm.RestoreOngoing, sm.RestoreExpiryTime)
+	}
+}
+
+func TestListOwner(t *testing.T) {
+	f := newFakeServer(t)
+	f.put("bucket", "a", "").owner = "owner"
+	store := f.newStorage(t, "bucket", "/")
+
+	it, err := store.List("", ps.WithListMode(types.ListModePrefix))
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	o, err := it.Next()
+	if err != nil {
+		t.Fatalf("next: %v", err)
+	}
+	sm := GetObjectSystemMetadata(o)
+	if sm.OwnerID != "owner" || sm.OwnerName != "owner" {
+		t.Errorf("owner is %s (%s), expected owner", sm.OwnerID, sm.OwnerName)
+	}
+}
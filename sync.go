@@ -0,0 +1,267 @@
+package minio
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/minio/minio-go/v7"
+	"golang.org/x/sync/errgroup"
+
+	ps "github.com/beyondstorage/go-storage/v4/pairs"
+	"github.com/beyondstorage/go-storage/v4/types"
+)
+
+const defaultSyncConcurrency = 4
+
+// SyncOptions is the options for Sync.
+type SyncOptions struct {
+	// Delete will delete objects in the destination that don't exist in the source.
+	Delete bool
+	// DryRun will only compare the listings without changing the destination.
+	DryRun bool
+	// Concurrency is the number of objects copied at the same time, default to 4.
+	Concurrency int
+}
+
+// SyncResult is the difference between the source and the destination, paths
+// are relative to the synced paths and sorted.
+type SyncResult struct {
+	// Added is the objects copied because they don't exist in the destination.
+	Added []string
+	// Updated is the objects copied because they are different in the destination.
+	Updated []string
+	// Deleted is the objects deleted from the destination, only set with Delete.
+	Deleted []string
+	// Unchanged is the number of objects skipped because they are the same.
+	Unchanged int
+}
+
+// Sync will make objects under dstPath of dst the same as objects under
+// srcPath of src, both of them could be any go-storage Storager.
+//
+// Paths are treated as dirs. Objects are considered different if their sizes
+// differ, or their etags differ while both storagers are this service and
+// neither etag is of a multipart upload, or the source is modified after the
+// destination. Objects will be copied on the server side if both storagers
+// are this service on the same endpoint with the same access key, and
+// streamed through otherwise.
+//
+// With DryRun, the returned result is what would be changed.
+func Sync(src types.Storager, srcPath string, dst types.Storager, dstPath string, opt SyncOptions) (r *SyncResult, err error) {
+	ctx := context.Background()
+	return SyncWithContext(ctx, src, srcPath, dst, dstPath, opt)
+}
+
+// SyncWithContext will make objects under dstPath of dst the same as objects
+// under srcPath of src.
+func SyncWithContext(ctx context.Context, src types.Storager, srcPath string, dst types.Storager, dstPath string, opt SyncOptions) (r *SyncResult, err error) {
+	srcObjects, err := listSyncObjects(ctx, src, srcPath)
+	if err != nil {
+		return nil, err
+	}
+	dstObjects, err := listSyncObjects(ctx, dst, dstPath)
+	if err != nil {
+		return nil, err
+	}
+
+	srcStore, _ := src.(*Storage)
+	dstStore, _ := dst.(*Storage)
+	// Etags are only comparable while objects are written by the same kind of service.
+	compareEtag := srcStore != nil && dstStore != nil
+
+	r = &SyncResult{}
+	var copies []string
+	for rel, so := range srcObjects {
+		do, ok := dstObjects[rel]
+		switch {
+		case !ok:
+			r.Added = append(r.Added, rel)
+		case isSyncObjectChanged(so, do, compareEtag):
+			r.Updated = append(r.Updated, rel)
+		default:
+			r.Unchanged++
+			continue
+		}
+		copies = append(copies, rel)
+	}
+	if opt.Delete {
+		for rel := range dstObjects {
+			if _, ok := srcObjects[rel]; !ok {
+				r.Deleted = append(r.Deleted, rel)
+			}
+		}
+	}
+	sort.Strings(r.Added)
+	sort.Strings(r.Updated)
+	sort.Strings(r.Deleted)
+	sort.Strings(copies)
+	if opt.DryRun {
+		return r, nil
+	}
+
+	concurrency := defaultSyncConcurrency
+	if opt.Concurrency > 0 {
+		concurrency = opt.Concurrency
+	}
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(concurrency)
+	for _, rel := range copies {
+		so := srcObjects[rel]
+		dp := joinSyncPath(dstPath, rel)
+		g.Go(func() error {
+			if srcStore != nil && dstStore != nil && dstStore.canCopyFrom(srcStore) {
+				return dstStore.copyFrom(gctx, srcStore, so, dp)
+			}
+			return streamSyncObject(gctx, src, so, dst, dp)
+		})
+	}
+	for _, rel := range r.Deleted {
+		do := dstObjects[rel]
+		g.Go(func() error {
+			return dst.DeleteWithContext(gctx, do.Path)
+		})
+	}
+	err = g.Wait()
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// listSyncObjects lists objects under the dir path keyed by the path relative to it.
+func listSyncObjects(ctx context.Context, store types.Storager, path string) (map[string]*types.Object, error) {
+	// Make sure `data` will not match `database/x`.
+	if path != "" && !strings.HasSuffix(path, "/") {
+		path += "/"
+	}
+	it, err := store.ListWithContext(ctx, path, ps.WithListMode(types.ListModePrefix))
+	if err != nil {
+		return nil, err
+	}
+
+	objects := make(map[string]*types.Object)
+	for {
+		o, err := it.Next()
+		if err != nil {
+			if errors.Is(err, types.IterateDone) {
+				return objects, nil
+			}
+			return nil, err
+		}
+		// Dirs are virtual in object storages.
+		if o.Mode.IsDir() {
+			continue
+		}
+		objects[strings.TrimPrefix(o.Path, path)] = o
+	}
+}
+
+func isSyncObjectChanged(src, dst *types.Object, compareEtag bool) bool {
+	if src.MustGetContentLength() != dst.MustGetContentLength() {
+		return true
+	}
+	if compareEtag {
+		se, sok := src.GetEtag()
+		de, dok := dst.GetEtag()
+		// Etags of multipart uploads like `<md5>-<parts>` depend on the part
+		// size, objects copied via ComposeObject will never have the same etag.
+		if sok && dok && !isMultipartEtag(se) && !isMultipartEtag(de) {
+			return se != de
+		}
+	}
+	sm, sok := src.GetLastModified()
+	dm, dok := dst.GetLastModified()
+	if sok && dok {
+		return sm.After(dm)
+	}
+	// Assume unchanged without anything else to compare.
+	return false
+}
+
+func isMultipartEtag(etag string) bool {
+	return strings.Contains(etag, "-")
+}
+
+func joinSyncPath(base, rel string) string {
+	if base == "" || strings.HasSuffix(base, "/") {
+		return base + rel
+	}
+	return base + "/" + rel
+}
+
+// streamSyncObject copies the object by reading it from src and writing into dst.
+func streamSyncObject(ctx context.Context, src types.Storager, so *types.Object, dst types.Storager, dp string) error {
+	var pairs []types.Pair
+	// Other services may not support content type.
+	if _, ok := dst.(*Storage); ok {
+		if ct, ok := so.GetContentType(); ok && ct != "" {
+			pairs = append(pairs, ps.WithContentType(ct))
+		}
+	}
+
+	pr, pw := io.Pipe()
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_, err := src.ReadWithContext(ctx, so.Path, pw)
+		pw.CloseWithError(err)
+	}()
+
+	_, err := dst.WriteWithContext(ctx, dp, pr, so.MustGetContentLength(), pairs...)
+	// Unblock the reader if write returned before consuming all data.
+	pr.CloseWithError(err)
+	wg.Wait()
+	return err
+}
+
+// canCopyFrom checks whether objects of from could be copied into s on the
+// server side, which requires the same endpoint and the same access key so
+// that the copy request signed by s could read from.
+func (s *Storage) canCopyFrom(from *Storage) bool {
+	if s.client.EndpointURL().String() != from.client.EndpointURL().String() {
+		return false
+	}
+	if s.creds == from.creds {
+		return true
+	}
+	if s.creds == nil || from.creds == nil {
+		return false
+	}
+	sv, err := s.creds.Get()
+	if err != nil {
+		return false
+	}
+	fv, err := from.creds.Get()
+	if err != nil {
+		return false
+	}
+	return sv.AccessKeyID == fv.AccessKeyID
+}
+
+// copyFrom copies the object o of from into path dst on the server side.
+func (s *Storage) copyFrom(ctx context.Context, from *Storage, o *types.Object, dst string) (err error) {
+	defer func() {
+		err = s.formatError("copy", err, o.Path, dst)
+	}()
+
+	srcOpts := minio.CopySrcOptions{
+		Bucket: from.bucket,
+		Object: o.ID,
+	}
+	dstOpts := minio.CopyDestOptions{
+		Bucket: s.bucket,
+		Object: s.getAbsPath(dst),
+	}
+	// ComposeObject will copy large objects via multipart upload.
+	if o.MustGetContentLength() > copySizeMaximum {
+		_, err = s.client.ComposeObject(ctx, dstOpts, srcOpts)
+		return err
+	}
+	_, err = s.client.CopyObject(ctx, dstOpts, srcOpts)
+	return err
+}
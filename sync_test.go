@@ -0,0 +1,231 @@
+package minio
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	ps "github.com/beyondstorage/go-storage/v4/pairs"
+	"github.com/beyondstorage/go-storage/v4/services"
+	"github.com/beyondstorage/go-storage/v4/types"
+)
+
+// memoryStorage is an in-memory Storager which supports just enough
+// operations for Sync.
+type memoryStorage struct {
+	types.UnimplementedStorager
+
+	mu      sync.Mutex
+	objects map[string]memoryObject
+}
+
+type memoryObject struct {
+	content      string
+	lastModified time.Time
+}
+
+// memoryPageStatus lists all objects in one page.
+type memoryPageStatus struct{}
+
+func (memoryPageStatus) ContinuationToken() string {
+	return ""
+}
+
+func newMemoryStorage(objects map[string]string, lastModified time.Time) *memoryStorage {
+	m := &memoryStorage{objects: make(map[string]memoryObject)}
+	for k, v := range objects {
+		m.objects[k] = memoryObject{content: v, lastModified: lastModified}
+	}
+	return m
+}
+
+func (m *memoryStorage) String() string {
+	return "memory"
+}
+
+func (m *memoryStorage) contents() map[string]string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	contents := make(map[string]string, len(m.objects))
+	for k, v := range m.objects {
+		contents[k] = v.content
+	}
+	return contents
+}
+
+func (m *memoryStorage) ListWithContext(ctx context.Context, path string, pairs ...types.Pair) (*types.ObjectIterator, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var objects []*types.Object
+	for k, v := range m.objects {
+		if !strings.HasPrefix(k, path) {
+			continue
+		}
+		o := types.NewObject(m, true)
+		o.ID, o.Path, o.Mode = k, k, types.ModeRead
+		o.SetContentLength(int64(len(v.content)))
+		o.SetLastModified(v.lastModified)
+		objects = append(objects, o)
+	}
+	sort.Slice(objects, func(i, j int) bool { return objects[i].Path < objects[j].Path })
+	return types.NewObjectIterator(ctx, func(ctx context.Context, page *types.ObjectPage) error {
+		page.Data = append(page.Data, objects...)
+		return types.IterateDone
+	}, memoryPageStatus{}), nil
+}
+
+func (m *memoryStorage) ReadWithContext(ctx context.Context, path string, w io.Writer, pairs ...types.Pair) (int64, error) {
+	m.mu.Lock()
+	v, ok := m.objects[path]
+	m.mu.Unlock()
+	if !ok {
+		return 0, services.ErrObjectNotExist
+	}
+	n, err := io.Copy(w, strings.NewReader(v.content))
+	return n, err
+}
+
+func (m *memoryStorage) WriteWithContext(ctx context.Context, path string, r io.Reader, size int64, pairs ...types.Pair) (int64, error) {
+	var buf bytes.Buffer
+	n, err := io.CopyN(&buf, r, size)
+	if err != nil {
+		return n, err
+	}
+	m.mu.Lock()
+	m.objects[path] = memoryObject{content: buf.String(), lastModified: time.Now()}
+	m.mu.Unlock()
+	return n, nil
+}
+
+func (m *memoryStorage) DeleteWithContext(ctx context.Context, path string, pairs ...types.Pair) error {
+	m.mu.Lock()
+	delete(m.objects, path)
+	m.mu.Unlock()
+	return nil
+}
+
+func TestSyncMemory(t *testing.T) {
+	now := time.Now()
+	src := newMemoryStorage(map[string]string{
+		"data/a":      "a",
+		"data/b/c":    "new",
+		"data/same":   "same",
+		"data/newer":  "old",
+		"database/x":  "x",
+		"data.backup": "x",
+	}, now)
+	dst := newMemoryStorage(map[string]string{
+		"backup/b/c":   "old content",
+		"backup/same":  "same",
+		"backup/newer": "new",
+		"backup/old":   "old",
+		"backups/x":    "x",
+	}, now.Add(-time.Hour))
+	// The destination is modified after the source.
+	dst.objects["backup/same"] = memoryObject{content: "same", lastModified: now.Add(time.Hour)}
+
+	expected := &SyncResult{
+		Added:     []string{"a"},
+		Updated:   []string{"b/c", "newer"},
+		Deleted:   []string{"old"},
+		Unchanged: 1,
+	}
+	before := dst.contents()
+	r, err := Sync(src, "data", dst, "backup", SyncOptions{Delete: true, DryRun: true})
+	if err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	if !reflect.DeepEqual(r, expected) {
+		t.Errorf("dry run returned %+v, expected %+v", r, expected)
+	}
+	if !reflect.DeepEqual(dst.contents(), before) {
+		t.Errorf("dry run changed the destination into %v", dst.contents())
+	}
+
+	r, err = Sync(src, "data", dst, "backup", SyncOptions{Delete: true})
+	if err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	if !reflect.DeepEqual(r, expected) {
+		t.Errorf("sync returned %+v, expected %+v", r, expected)
+	}
+	expectedContents := map[string]string{
+		"backup/a":     "a",
+		"backup/b/c":   "new",
+		"backup/same":  "same",
+		"backup/newer": "old",
+		"backups/x":    "x",
+	}
+	if actual := dst.contents(); !reflect.DeepEqual(actual, expectedContents) {
+		t.Errorf("sync made the destination %v, expected %v", actual, expectedContents)
+	}
+
+	// Nothing to do after synced.
+	r, err = Sync(src, "data/", dst, "backup/", SyncOptions{Delete: true})
+	if err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	if len(r.Added)+len(r.Updated)+len(r.Deleted) != 0 || r.Unchanged != 4 {
+		t.Errorf("sync again returned %+v, expected nothing changed", r)
+	}
+}
+
+func TestSyncServerSideCopy(t *testing.T) {
+	f := newFakeServer(t)
+	f.put("bucket", "src/a", "a")
+	f.put("bucket", "src/b", "b")
+	// Objects copied via ComposeObject have a multipart etag.
+	f.put("bucket", "dst/b", "b").etag = "0123456789abcdef0123456789abcdef-2"
+	src := f.newStorage(t, "bucket", "/src/")
+	dst := f.newStorage(t, "bucket", "/dst/")
+
+	r, err := Sync(src, "", dst, "", SyncOptions{})
+	if err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	if !reflect.DeepEqual(r.Added, []string{"a"}) || len(r.Updated) != 0 || r.Unchanged != 1 {
+		t.Errorf("sync returned %+v, expected a added and b unchanged", r)
+	}
+	if n := f.count("CopyObject"); n != 1 {
+		t.Errorf("sync sent %d CopyObject requests, expected 1", n)
+	}
+	if n := f.count("PutObject"); n != 0 {
+		t.Errorf("sync sent %d PutObject requests, expected 0", n)
+	}
+}
+
+func TestSyncDifferentCredentials(t *testing.T) {
+	f := newFakeServer(t)
+	f.put("bucket", "src/a", "a").contentType = "text/plain"
+	src := f.newStorage(t, "bucket", "/src/")
+	_, other, err := newServicerAndStorager(
+		ps.WithCredential("hmac:other:sk"),
+		ps.WithEndpoint("http:"+strings.TrimPrefix(f.URL, "http://")),
+		ps.WithName("bucket"),
+		ps.WithWorkDir("/dst/"),
+	)
+	if err != nil {
+		t.Fatalf("new storager: %v", err)
+	}
+
+	// The access key of dst may not read src, objects should be streamed.
+	_, err = Sync(src, "", other, "", SyncOptions{})
+	if err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	if n := f.count("CopyObject"); n != 0 {
+		t.Errorf("sync sent %d CopyObject requests, expected 0", n)
+	}
+	o, ok := f.get("bucket", "dst/a")
+	if !ok || string(o.content) != "a" {
+		t.Fatalf("sync didn't write dst/a")
+	}
+}
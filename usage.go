@@ -0,0 +1,186 @@
+package minio
+
+import (
+	"container/heap"
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/minio/minio-go/v7"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/beyondstorage/go-storage/v4/types"
+)
+
+const (
+	defaultUsageConcurrency    = 4
+	defaultUsageLargestObjects = 10
+	// usageProgressInterval is the number of objects between two progress callbacks.
+	usageProgressInterval = 1000
+)
+
+// Usage is the usage summary of objects under a path.
+type Usage struct {
+	ObjectCount int64
+	TotalSize   int64
+	// StorageClasses is the usage of every storage class.
+	StorageClasses map[string]UsageStat
+	// LargestObjects is the largest objects sorted by content length in descending order.
+	LargestObjects []*types.Object
+}
+
+// UsageStat is the object count and total size of a part of objects.
+type UsageStat struct {
+	ObjectCount int64
+	TotalSize   int64
+}
+
+// UsageOptions is the options for Usage.
+type UsageOptions struct {
+	// Concurrency is the number of first level dirs walked at the same time, default to 4.
+	Concurrency int
+	// LargestObjects is the number of largest objects returned, default to 10.
+	LargestObjects int
+	// ProgressFunc will be called with the current object count and total size
+	// every 1000 objects. Calls are serialized.
+	ProgressFunc func(objectCount, totalSize int64)
+}
+
+// Usage will walk all objects under path and return the usage summary.
+//
+// Dir marker objects are not counted.
+func (s *Storage) Usage(path string, opt UsageOptions) (u *Usage, err error) {
+	ctx := context.Background()
+	return s.UsageWithContext(ctx, path, opt)
+}
+
+// UsageWithContext will walk all objects under path and return the usage summary.
+func (s *Storage) UsageWithContext(ctx context.Context, path string, opt UsageOptions) (u *Usage, err error) {
+	defer func() {
+		err = s.formatError("usage", err, path)
+	}()
+
+	return s.usage(ctx, path, opt)
+}
+
+func (s *Storage) usage(ctx context.Context, path string, opt UsageOptions) (u *Usage, err error) {
+	rp := s.getAbsPath(path)
+
+	concurrency := defaultUsageConcurrency
+	if opt.Concurrency > 0 {
+		concurrency = opt.Concurrency
+	}
+	c := &usageCollector{
+		usage: Usage{
+			StorageClasses: make(map[string]UsageStat),
+		},
+		largestObjects: defaultUsageLargestObjects,
+		progressFunc:   opt.ProgressFunc,
+	}
+	if opt.LargestObjects > 0 {
+		c.largestObjects = opt.LargestObjects
+	}
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(concurrency)
+
+	// Objects in the first level will be collected directly, and every
+	// common prefix will be walked recursively in its own goroutine.
+	err = s.walkObjects(gctx, minio.ListObjectsOptions{Prefix: rp}, func(v minio.ObjectInfo) error {
+		if !strings.HasSuffix(v.Key, "/") {
+			c.add(v)
+			return nil
+		}
+		// Skip the marker object of the dir itself.
+		if v.Key == rp {
+			return nil
+		}
+
+		prefix := v.Key
+		g.Go(func() error {
+			return s.walkObjects(gctx, minio.ListObjectsOptions{Prefix: prefix, Recursive: true}, func(v minio.ObjectInfo) error {
+				if !strings.HasSuffix(v.Key, "/") {
+					c.add(v)
+				}
+				return nil
+			})
+		})
+		return nil
+	})
+	// Wait for all started goroutines even if the first level walk failed.
+	if gerr := g.Wait(); err == nil {
+		err = gerr
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	u = &c.usage
+	largest := make([]minio.ObjectInfo, len(c.largest))
+	copy(largest, c.largest)
+	sort.Slice(largest, func(i, j int) bool {
+		return largest[i].Size > largest[j].Size
+	})
+	for _, v := range largest {
+		o, err := s.formatFileObject(v)
+		if err != nil {
+			return nil, err
+		}
+		u.LargestObjects = append(u.LargestObjects, o)
+	}
+	return u, nil
+}
+
+// usageCollector collects usage from multiple goroutines.
+type usageCollector struct {
+	mu sync.Mutex
+
+	usage          Usage
+	largest        objectSizeHeap
+	largestObjects int
+	progressFunc   func(objectCount, totalSize int64)
+}
+
+func (c *usageCollector) add(v minio.ObjectInfo) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.usage.ObjectCount++
+	c.usage.TotalSize += v.Size
+
+	stat := c.usage.StorageClasses[v.StorageClass]
+	stat.ObjectCount++
+	stat.TotalSize += v.Size
+	c.usage.StorageClasses[v.StorageClass] = stat
+
+	if len(c.largest) < c.largestObjects {
+		heap.Push(&c.largest, v)
+	} else if v.Size > c.largest[0].Size {
+		c.largest[0] = v
+		heap.Fix(&c.largest, 0)
+	}
+
+	if c.progressFunc != nil && c.usage.ObjectCount%usageProgressInterval == 0 {
+		c.progressFunc(c.usage.ObjectCount, c.usage.TotalSize)
+	}
+}
+
+// objectSizeHeap is a min heap of objects ordered by size.
+type objectSizeHeap []minio.ObjectInfo
+
+func (h objectSizeHeap) Len() int           { return len(h) }
+func (h objectSizeHeap) Less(i, j int) bool { return h[i].Size < h[j].Size }
+func (h objectSizeHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *objectSizeHeap) Push(x interface{}) {
+	*h = append(*h, x.(minio.ObjectInfo))
+}
+
+func (h *objectSizeHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+	return x
+}
@@ -0,0 +1,98 @@
+package minio
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/minio/minio-go/v7"
+)
+
+func TestUsage(t *testing.T) {
+	f := newFakeServer(t)
+	f.put("bucket", "p/", "")
+	f.put("bucket", "p/a", "1")
+	f.put("bucket", "p/b/", "")
+	f.put("bucket", "p/b/c", "22")
+	f.put("bucket", "p/b/d/e", "4444")
+	f.put("bucket", "p/f/g", "333").storageClass = "GLACIER"
+	f.put("bucket", "q/h", "55555")
+	store := f.newStorage(t, "bucket", "/")
+
+	for _, concurrency := range []int{1, 4} {
+		u, err := store.Usage("p/", UsageOptions{Concurrency: concurrency, LargestObjects: 2})
+		if err != nil {
+			t.Fatalf("usage: %v", err)
+		}
+		if u.ObjectCount != 4 || u.TotalSize != 10 {
+			t.Errorf("usage has %d objects of %d bytes, expected 4 objects of 10 bytes", u.ObjectCount, u.TotalSize)
+		}
+		expected := map[string]UsageStat{
+			"STANDARD": {ObjectCount: 3, TotalSize: 7},
+			"GLACIER":  {ObjectCount: 1, TotalSize: 3},
+		}
+		if !reflect.DeepEqual(u.StorageClasses, expected) {
+			t.Errorf("usage has storage classes %v, expected %v", u.StorageClasses, expected)
+		}
+		var largest []string
+		for _, o := range u.LargestObjects {
+			largest = append(largest, o.Path)
+		}
+		if !reflect.DeepEqual(largest, []string{"p/b/d/e", "p/f/g"}) {
+			t.Errorf("usage has largest objects %v, expected [p/b/d/e p/f/g]", largest)
+		}
+	}
+}
+
+func TestUsageProgress(t *testing.T) {
+	f := newFakeServer(t)
+	for i := 0; i < 2500; i++ {
+		f.put("bucket", fmt.Sprintf("p/%d/%d", i%3, i), "x")
+	}
+	store := f.newStorage(t, "bucket", "/")
+
+	var progress []int64
+	u, err := store.Usage("p/", UsageOptions{
+		ProgressFunc: func(objectCount, totalSize int64) {
+			if objectCount != totalSize {
+				t.Errorf("progress has %d objects of %d bytes", objectCount, totalSize)
+			}
+			progress = append(progress, objectCount)
+		},
+	})
+	if err != nil {
+		t.Fatalf("usage: %v", err)
+	}
+	if u.ObjectCount != 2500 {
+		t.Errorf("usage has %d objects, expected 2500", u.ObjectCount)
+	}
+	if !reflect.DeepEqual(progress, []int64{1000, 2000}) {
+		t.Errorf("progress is called with %v, expected [1000 2000]", progress)
+	}
+}
+
+func TestUsageCollectorLargest(t *testing.T) {
+	c := &usageCollector{
+		usage:          Usage{StorageClasses: make(map[string]UsageStat)},
+		largestObjects: 5,
+	}
+	r := rand.New(rand.NewSource(1))
+	var sizes []int64
+	for i := 0; i < 100; i++ {
+		size := r.Int63n(1000)
+		sizes = append(sizes, size)
+		c.add(minio.ObjectInfo{Key: fmt.Sprint(i), Size: size})
+	}
+
+	sort.Slice(sizes, func(i, j int) bool { return sizes[i] > sizes[j] })
+	var actual []int64
+	for _, v := range c.largest {
+		actual = append(actual, v.Size)
+	}
+	sort.Slice(actual, func(i, j int) bool { return actual[i] > actual[j] })
+	if !reflect.DeepEqual(actual, sizes[:5]) {
+		t.Errorf("largest sizes are %v, expected %v", actual, sizes[:5])
+	}
+}
@@ -1,16 +1,22 @@
 package minio
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/minio/minio-go/v7"
 	"github.com/minio/minio-go/v7/pkg/credentials"
 
 	"github.com/beyondstorage/go-endpoint"
 	ps "github.com/beyondstorage/go-storage/v4/pairs"
-	"github.com/beyondstorage/go-storage/v4/pkg/credential"
 	"github.com/beyondstorage/go-storage/v4/services"
 	"github.com/beyondstorage/go-storage/v4/types"
 )
@@ -18,6 +24,7 @@ import (
 // Service is the minio service.
 type Service struct {
 	service *minio.Client
+	creds   *credentials.Credentials
 
 	defaultPairs DefaultServicePairs
 	features     ServiceFeatures
@@ -32,9 +39,12 @@ func (s *Service) String() string {
 // Storage is the example client.
 type Storage struct {
 	client *minio.Client
+	creds  *credentials.Credentials
 
 	bucket  string
 	workDir string
+	// location is the region of the bucket, empty if not known.
+	location string
 
 	defaultPairs DefaultStoragePairs
 	features     StorageFeatures
@@ -80,37 +90,34 @@ func newServicer(pairs ...types.Pair) (srv *Service, err error) {
 		return nil, err
 	}
 
-	cp, err := credential.Parse(opt.Credential)
-	if err != nil {
-		return nil, err
-	}
-	if cp.Protocol() != credential.ProtocolHmac {
-		return nil, services.PairUnsupportedError{Pair: ps.WithCredential(opt.Credential)}
-	}
-	ak, sk := cp.Hmac()
-
 	ep, err := endpoint.Parse(opt.Endpoint)
 	if err != nil {
 		return nil, err
 	}
 
-	var host string
+	var epURL, host string
 	var port int
 	var secure bool
 	switch ep.Protocol() {
 	case endpoint.ProtocolHTTP:
-		_, host, port = ep.HTTP()
+		epURL, host, port = ep.HTTP()
 		secure = false
 	case endpoint.ProtocolHTTPS:
-		_, host, port = ep.HTTPS()
+		epURL, host, port = ep.HTTPS()
 		secure = true
 	default:
 		return nil, services.PairUnsupportedError{Pair: ps.WithEndpoint(opt.Endpoint)}
 	}
 	url := fmt.Sprintf("%s:%d", host, port)
 
+	creds, err := newCredentials(opt, epURL)
+	if err != nil {
+		return nil, err
+	}
+
+	srv.creds = creds
 	srv.service, err = minio.New(url, &minio.Options{
-		Creds:  credentials.NewStaticV4(ak, sk, ""),
+		Creds:  creds,
 		Secure: secure,
 	})
 	if err != nil {
@@ -141,6 +148,20 @@ func newServicerAndStorager(pairs ...types.Pair) (srv *Service, store *Storage,
 	return srv, store, nil
 }
 
+var (
+	// ErrPreconditionFailed will be returned while the conditions given by
+	// if_match, if_none_match, if_modified_since or if_unmodified_since are not met.
+	//
+	// For write, it means the object has been changed by others (if_match) or
+	// already exists (if_none_match with `*`).
+	ErrPreconditionFailed = services.NewErrorCode("precondition failed")
+	// ErrObjectArchived will be returned while reading an object that has been
+	// transitioned to an archive tier and not restored yet.
+	//
+	// The object could be restored via Restore.
+	ErrObjectArchived = services.NewErrorCode("object archived")
+)
+
 func formatError(err error) error {
 	if _, ok := err.(services.InternalError); ok {
 		return err
@@ -155,9 +176,19 @@ func formatError(err error) error {
 			return fmt.Errorf("%w, %v", services.ErrObjectNotExist, err)
 		case "InternalError":
 			return fmt.Errorf("%w, %v", services.ErrServiceInternal, err)
+		case "PreconditionFailed":
+			return fmt.Errorf("%w, %v", ErrPreconditionFailed, err)
+		case "InvalidObjectState":
+			return fmt.Errorf("%w, %v", ErrObjectArchived, err)
 		}
 
 		switch e.StatusCode {
+		// GET and HEAD requests with unmet if_none_match or if_modified_since
+		// will be responded with 304 Not Modified instead of 412.
+		case http.StatusNotModified:
+			return fmt.Errorf("%w, %v", ErrPreconditionFailed, err)
+		case http.StatusPreconditionFailed:
+			return fmt.Errorf("%w, %v", ErrPreconditionFailed, err)
 		case http.StatusTooManyRequests:
 			return fmt.Errorf("%w, %v", services.ErrRequestThrottled, err)
 		case http.StatusServiceUnavailable:
@@ -176,6 +207,7 @@ func (s *Service) newStorage(pairs ...types.Pair) (st *Storage, err error) {
 
 	store := &Storage{
 		client:  s.service,
+		creds:   s.creds,
 		bucket:  opt.Name,
 		workDir: "/",
 	}
@@ -186,6 +218,9 @@ func (s *Service) newStorage(pairs ...types.Pair) (st *Storage, err error) {
 		}
 		store.workDir = opt.WorkDir
 	}
+	if opt.HasLocation {
+		store.location = opt.Location
+	}
 	if opt.HasDefaultStoragePairs {
 		store.defaultPairs = opt.DefaultStoragePairs
 	}
@@ -237,21 +272,357 @@ func (s *Storage) getRelPath(path string) string {
 	return strings.TrimPrefix(path, prefix)
 }
 
+// listObjectPage will fetch the next page of objects via one ListObjectsV2
+// request, common prefixes are returned as objects with the prefix as key.
+//
+// Every page is exactly one response, so that the listing could be resumed
+// by the continuation token returned by the server without missing entries.
+func (s *Storage) listObjectPage(ctx context.Context, input *objectPageStatus) (infos []minio.ObjectInfo, done bool, err error) {
+	// minio.Core doesn't accept context, check it before sending every request.
+	if err = ctx.Err(); err != nil {
+		return nil, false, err
+	}
+
+	// The request in flight can't be aborted, so wait for it in another
+	// goroutine and give up once ctx is done.
+	type listResult struct {
+		result minio.ListBucketV2Result
+		err    error
+	}
+	ch := make(chan listResult, 1)
+	go func(prefix, startAfter, continuationToken, delimiter string, maxKeys int) {
+		core := minio.Core{Client: s.client}
+		result, err := core.ListObjectsV2(s.bucket, prefix, startAfter,
+			continuationToken, delimiter, maxKeys)
+		ch <- listResult{result, err}
+	}(input.prefix, input.startAfter, input.continuationToken, input.delimiter, input.maxKeys)
+
+	var result minio.ListBucketV2Result
+	select {
+	case <-ctx.Done():
+		return nil, false, ctx.Err()
+	case v := <-ch:
+		if v.err != nil {
+			return nil, false, v.err
+		}
+		result = v.result
+	}
+
+	seen := make(map[string]bool, len(result.Contents))
+	for _, v := range result.Contents {
+		// Skip the marker object of the dir itself while listing a dir.
+		if input.delimiter != "" && v.Key == input.prefix {
+			continue
+		}
+		v.ETag = strings.Trim(v.ETag, "\"")
+		infos = append(infos, v)
+		seen[v.Key] = true
+	}
+	if input.withMetadata && len(infos) > 0 {
+		err = s.fillListedMetadata(ctx, input, infos)
+		if err != nil {
+			return nil, false, err
+		}
+	}
+	for _, v := range result.CommonPrefixes {
+		// Keys under the dir that start_after points to will be rolled up into
+		// it again, while it has been returned before start_after.
+		if input.startAfter != "" && strings.TrimSuffix(v.Prefix, "/") == strings.TrimSuffix(input.startAfter, "/") {
+			continue
+		}
+		// The dir could be returned as both a marker object and a prefix.
+		if seen[v.Prefix] {
+			continue
+		}
+		seen[v.Prefix] = true
+		infos = append(infos, minio.ObjectInfo{Key: v.Prefix})
+	}
+
+	// Common prefixes are sent after objects in the same response, sort them
+	// so that entries are returned in order.
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Key < infos[j].Key
+	})
+	if len(infos) > 0 {
+		input.lastKey = infos[len(infos)-1].Key
+	}
+	input.startAfter = ""
+	input.continuationToken = result.NextContinuationToken
+	return infos, !result.IsTruncated, nil
+}
+
+// Keys of the markers in the continuation token of ListModePart.
+const (
+	partKeyMarker      = "key-marker"
+	partUploadIDMarker = "upload-id-marker"
+)
+
+// newPartPageStatus returns the status to list incomplete multipart uploads
+// under rp.
+//
+// Incomplete uploads don't have content length or metadata, so list_metadata
+// and filters are not supported.
+func (s *Storage) newPartPageStatus(rp string, pageSize int, opt pairStorageList) (*partPageStatus, error) {
+	for _, v := range opt.pairs {
+		switch v.Key {
+		case "list_metadata", "glob", "regexp", "min_size", "max_size", "modified_after", "modified_before":
+			return nil, services.PairUnsupportedError{Pair: v}
+		}
+	}
+
+	input := &partPageStatus{
+		maxUploads: pageSize,
+		prefix:     rp,
+	}
+	if opt.HasStartAfter {
+		input.keyMarker = s.getAbsPath(opt.StartAfter)
+	}
+	if opt.HasContinuationToken {
+		markers, err := url.ParseQuery(opt.ContinuationToken)
+		if err != nil {
+			return nil, fmt.Errorf("continuation token %q is invalid: %w", opt.ContinuationToken, err)
+		}
+		input.keyMarker = markers.Get(partKeyMarker)
+		input.uploadIDMarker = markers.Get(partUploadIDMarker)
+	}
+	return input, nil
+}
+
+// errListedMetadataFilled is used to stop walking objects once all objects
+// in the page have been filled.
+var errListedMetadataFilled = errors.New("listed metadata filled")
+
+// fillListedMetadata will fill content type and user metadata of objects in
+// infos, which are sorted by key.
+//
+// Listing with metadata is a MinIO extension which minio.Core doesn't support,
+// so objects in the page are listed again with metadata after the last key of
+// the previous page. Objects will be stat-ed one by one instead if the listing
+// is resumed via continuation token, whose start is unknown.
+func (s *Storage) fillListedMetadata(ctx context.Context, input *objectPageStatus, infos []minio.ObjectInfo) error {
+	if input.lastKey == "" && input.continuationToken != "" {
+		for i, v := range infos {
+			output, err := s.client.StatObject(ctx, s.bucket, v.Key, minio.StatObjectOptions{})
+			if err != nil {
+				return err
+			}
+			infos[i].ContentType = output.ContentType
+			infos[i].UserMetadata = output.UserMetadata
+		}
+		return nil
+	}
+
+	options := minio.ListObjectsOptions{
+		Prefix:       input.prefix,
+		StartAfter:   input.lastKey,
+		Recursive:    input.delimiter == "",
+		WithMetadata: true,
+		MaxKeys:      input.maxKeys,
+	}
+	last := infos[len(infos)-1].Key
+	idx := 0
+	err := s.walkObjects(ctx, options, func(v minio.ObjectInfo) error {
+		// Objects are sent in order, while common prefixes are sent among them.
+		if !options.Recursive && strings.HasSuffix(v.Key, "/") {
+			return nil
+		}
+		for idx < len(infos) && infos[idx].Key < v.Key {
+			idx++
+		}
+		if idx < len(infos) && infos[idx].Key == v.Key {
+			formatListedMetadata(&v)
+			infos[idx].ContentType = v.ContentType
+			infos[idx].UserMetadata = v.UserMetadata
+		}
+		if v.Key >= last {
+			return errListedMetadataFilled
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errListedMetadataFilled) {
+		return err
+	}
+	return nil
+}
+
+// walkObjects will call fn for every object listed with options until fn returns an error.
+func (s *Storage) walkObjects(ctx context.Context, options minio.ListObjectsOptions, fn func(v minio.ObjectInfo) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	objChan := s.client.ListObjects(ctx, s.bucket, options)
+	defer func() {
+		cancel()
+		// Drain the channel so that the listing goroutine could exit.
+		for range objChan {
+		}
+	}()
+
+	for v := range objChan {
+		if v.Err != nil {
+			return v.Err
+		}
+		if err := fn(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// formatListedMetadata will convert metadata returned by list into the same
+// format as stat.
+//
+// List with metadata returns content type and user metadata with
+// the `X-Amz-Meta-` prefix together in UserMetadata.
+func formatListedMetadata(v *minio.ObjectInfo) {
+	const userMetadataPrefix = "X-Amz-Meta-"
+
+	um := make(minio.StringMap)
+	for k, value := range v.UserMetadata {
+		switch {
+		case strings.EqualFold(k, "Content-Type"):
+			v.ContentType = value
+		case len(k) > len(userMetadataPrefix) && strings.EqualFold(k[:len(userMetadataPrefix)], userMetadataPrefix):
+			um[k[len(userMetadataPrefix):]] = value
+		}
+	}
+	v.UserMetadata = um
+}
+
+// objectFilter filters listed objects on the client side.
+type objectFilter struct {
+	glob           string
+	regexp         *regexp.Regexp
+	hasMinSize     bool
+	minSize        int64
+	hasMaxSize     bool
+	maxSize        int64
+	modifiedAfter  time.Time
+	modifiedBefore time.Time
+}
+
+func newObjectFilter(opt pairStorageList) (f objectFilter, err error) {
+	if opt.HasGlob {
+		// Check the pattern here so that we will not fail in the middle of listing.
+		if _, err = path.Match(opt.Glob, ""); err != nil {
+			return objectFilter{}, err
+		}
+		f.glob = opt.Glob
+	}
+	if opt.HasRegexp {
+		f.regexp, err = regexp.Compile(opt.Regexp)
+		if err != nil {
+			return objectFilter{}, err
+		}
+	}
+	if opt.HasMinSize {
+		f.hasMinSize = true
+		f.minSize = opt.MinSize
+	}
+	if opt.HasMaxSize {
+		f.hasMaxSize = true
+		f.maxSize = opt.MaxSize
+	}
+	if opt.HasModifiedAfter {
+		f.modifiedAfter = opt.ModifiedAfter
+	}
+	if opt.HasModifiedBefore {
+		f.modifiedBefore = opt.ModifiedBefore
+	}
+	return f, nil
+}
+
+func (f objectFilter) match(o *types.Object) bool {
+	// Dirs are always kept so that users can walk into them.
+	if o.Mode.IsDir() {
+		return true
+	}
+	if f.glob != "" {
+		if ok, _ := path.Match(f.glob, o.Path); !ok {
+			return false
+		}
+	}
+	if f.regexp != nil && !f.regexp.MatchString(o.Path) {
+		return false
+	}
+
+	size := o.MustGetContentLength()
+	if f.hasMinSize && size < f.minSize {
+		return false
+	}
+	if f.hasMaxSize && size > f.maxSize {
+		return false
+	}
+
+	lastModified := o.MustGetLastModified()
+	if !f.modifiedAfter.IsZero() && !lastModified.After(f.modifiedAfter) {
+		return false
+	}
+	if !f.modifiedBefore.IsZero() && !lastModified.Before(f.modifiedBefore) {
+		return false
+	}
+	return true
+}
+
 func (s *Storage) formatFileObject(v minio.ObjectInfo) (o *types.Object, err error) {
 	o = s.newObject(true)
-	if v.ETag == "" {
+	// Both common prefixes and dir marker objects end with "/".
+	if strings.HasSuffix(v.Key, "/") {
 		o.Mode |= types.ModeDir
 	} else {
 		o.Mode |= types.ModeRead
 	}
 
 	o.SetID(v.Key)
-	o.SetPath(s.getRelPath(v.Key))
+	if o.Mode.IsDir() {
+		// Keep the same with the dir object returned by create, whose path
+		// doesn't have the trailing slash.
+		o.SetPath(strings.TrimSuffix(s.getRelPath(v.Key), "/"))
+	} else {
+		o.SetPath(s.getRelPath(v.Key))
+	}
 	o.SetEtag(v.ETag)
 	o.SetContentLength(v.Size)
 	o.SetContentType(v.ContentType)
 	o.SetLastModified(v.LastModified)
 	o.SetUserMetadata(v.UserMetadata)
+
+	sm := ObjectSystemMetadata{
+		ChecksumCrc32:        v.ChecksumCRC32,
+		ChecksumCrc32c:       v.ChecksumCRC32C,
+		ChecksumSha1:         v.ChecksumSHA1,
+		ChecksumSha256:       v.ChecksumSHA256,
+		Expiration:           v.Expiration,
+		ExpirationRuleID:     v.ExpirationRuleID,
+		OwnerID:              v.Owner.ID,
+		OwnerName:            v.Owner.DisplayName,
+		ReplicationStatus:    v.ReplicationStatus,
+		ServerSideEncryption: v.Metadata.Get("X-Amz-Server-Side-Encryption"),
+		StorageClass:         v.StorageClass,
+		TagCount:             v.UserTagCount,
+		VersionID:            v.VersionID,
+	}
+	// StatObject doesn't fill StorageClass, which is left in the headers.
+	if sm.StorageClass == "" {
+		sm.StorageClass = v.Metadata.Get("X-Amz-Storage-Class")
+	}
+	if v.Restore != nil {
+		sm.RestoreOngoing = v.Restore.OngoingRestore
+		sm.RestoreExpiryTime = v.Restore.ExpiryTime
+	}
+	o.SetSystemMetadata(sm)
+
+	return
+}
+
+func (s *Storage) formatPartObject(v minio.ObjectMultipartInfo) (o *types.Object) {
+	o = s.newObject(true)
+	o.Mode |= types.ModePart
+
+	o.SetID(v.Key)
+	o.SetPath(s.getRelPath(v.Key))
+	o.SetMultipartID(v.UploadID)
+	// Content length is left unset, as incomplete uploads listed by S3 don't
+	// have the size of uploaded parts.
+	o.SetLastModified(v.Initiated)
 	o.SetSystemMetadata(ObjectSystemMetadata{
 		StorageClass: v.StorageClass,
 	})
@@ -0,0 +1,84 @@
+package minio
+
+import (
+	"context"
+
+	"github.com/minio/minio-go/v7"
+)
+
+const (
+	// VersioningEnabled means versioning is enabled for the bucket.
+	VersioningEnabled = minio.Enabled
+	// VersioningSuspended means versioning is suspended for the bucket.
+	VersioningSuspended = minio.Suspended
+)
+
+// VersioningConfig is the versioning configuration of a bucket.
+type VersioningConfig struct {
+	// Status is VersioningEnabled, VersioningSuspended or empty if versioning
+	// has never been enabled.
+	Status string
+	// ExcludedPrefixes are the prefixes that will not be versioned.
+	//
+	// This is a MinIO extension and requires versioning to be enabled.
+	ExcludedPrefixes []string
+	// ExcludeFolders will make dir marker objects not versioned.
+	//
+	// This is a MinIO extension and requires versioning to be enabled.
+	ExcludeFolders bool
+}
+
+// GetVersioning will get the versioning configuration of bucket name.
+func (s *Service) GetVersioning(name string) (cfg VersioningConfig, err error) {
+	ctx := context.Background()
+	return s.GetVersioningWithContext(ctx, name)
+}
+
+// GetVersioningWithContext will get the versioning configuration of bucket name.
+func (s *Service) GetVersioningWithContext(ctx context.Context, name string) (cfg VersioningConfig, err error) {
+	defer func() {
+		err = s.formatError("get_versioning", err, name)
+	}()
+
+	return getVersioning(ctx, s.service, name)
+}
+
+// SetVersioning will enable or suspend versioning of bucket name.
+func (s *Service) SetVersioning(name string, cfg VersioningConfig) (err error) {
+	ctx := context.Background()
+	return s.SetVersioningWithContext(ctx, name, cfg)
+}
+
+// SetVersioningWithContext will enable or suspend versioning of bucket name.
+func (s *Service) SetVersioningWithContext(ctx context.Context, name string, cfg VersioningConfig) (err error) {
+	defer func() {
+		err = s.formatError("set_versioning", err, name)
+	}()
+
+	return s.setVersioning(ctx, name, cfg)
+}
+
+func getVersioning(ctx context.Context, client *minio.Client, name string) (cfg VersioningConfig, err error) {
+	output, err := client.GetBucketVersioning(ctx, name)
+	if err != nil {
+		return VersioningConfig{}, err
+	}
+
+	cfg.Status = output.Status
+	for _, v := range output.ExcludedPrefixes {
+		cfg.ExcludedPrefixes = append(cfg.ExcludedPrefixes, v.Prefix)
+	}
+	cfg.ExcludeFolders = output.ExcludeFolders
+	return cfg, nil
+}
+
+func (s *Service) setVersioning(ctx context.Context, name string, cfg VersioningConfig) (err error) {
+	input := minio.BucketVersioningConfiguration{
+		Status:         cfg.Status,
+		ExcludeFolders: cfg.ExcludeFolders,
+	}
+	for _, v := range cfg.ExcludedPrefixes {
+		input.ExcludedPrefixes = append(input.ExcludedPrefixes, minio.ExcludedPrefix{Prefix: v})
+	}
+	return s.service.SetBucketVersioning(ctx, name, input)
+}
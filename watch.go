@@ -0,0 +1,261 @@
+package minio
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/notification"
+
+	"github.com/beyondstorage/go-storage/v4/types"
+)
+
+const (
+	// watchBackoffMinimum is the delay before the first reconnection.
+	watchBackoffMinimum = time.Second
+	// watchBackoffMaximum is the max delay between two reconnections.
+	watchBackoffMaximum = 30 * time.Second
+)
+
+// EventType is the type of object event.
+type EventType string
+
+const (
+	// EventCreated will be sent while an object has been created or overwritten.
+	EventCreated EventType = "created"
+	// EventRemoved will be sent while an object has been removed.
+	EventRemoved EventType = "removed"
+	// EventAccessed will be sent while an object has been read or stated.
+	EventAccessed EventType = "accessed"
+)
+
+// notificationEvents returns the wildcard S3 event name of the event type.
+func (t EventType) notificationEvents() (string, bool) {
+	switch t {
+	case EventCreated:
+		return string(notification.ObjectCreatedAll), true
+	case EventRemoved:
+		return string(notification.ObjectRemovedAll), true
+	case EventAccessed:
+		return string(notification.ObjectAccessedAll), true
+	default:
+		return "", false
+	}
+}
+
+// Event is an object event sent by Watch.
+type Event struct {
+	Type EventType
+	// Name is the S3 event name, like `s3:ObjectCreated:Put`.
+	Name string
+	Time time.Time
+	// Object is built from the event without extra requests, so only
+	// metadata carried by the event will be set.
+	Object *types.Object
+}
+
+// WatchOptions is the options for Watch.
+type WatchOptions struct {
+	// Suffix will only watch objects whose path ends with it, like `.jpg`.
+	Suffix string
+	// Types is the event types to watch, default to all event types.
+	Types []EventType
+}
+
+// Watch will call fn with events of objects under path until fn returns an error.
+//
+// Watch is only supported by MinIO servers. Dropped streams will be reconnected
+// with backoff, and events happened during reconnection will be lost.
+func (s *Storage) Watch(path string, opt WatchOptions, fn func(e Event) error) (err error) {
+	ctx := context.Background()
+	return s.WatchWithContext(ctx, path, opt, fn)
+}
+
+// WatchWithContext will call fn with events of objects under path until ctx
+// is done or fn returns an error.
+//
+// nil will be returned if the watching is stopped by ctx, and errors returned
+// by fn will be returned as is.
+func (s *Storage) WatchWithContext(ctx context.Context, path string, opt WatchOptions, fn func(e Event) error) (err error) {
+	defer func() {
+		if ce, ok := err.(watchCallbackError); ok {
+			err = ce.err
+			return
+		}
+		err = s.formatError("watch", err, path)
+	}()
+
+	return s.watch(ctx, path, opt, fn)
+}
+
+func (s *Storage) watch(ctx context.Context, path string, opt WatchOptions, fn func(e Event) error) (err error) {
+	rp := s.getAbsPath(path)
+
+	eventTypes := opt.Types
+	if len(eventTypes) == 0 {
+		eventTypes = []EventType{EventCreated, EventRemoved, EventAccessed}
+	}
+	events := make([]string, 0, len(eventTypes))
+	for _, v := range eventTypes {
+		name, ok := v.notificationEvents()
+		if !ok {
+			return fmt.Errorf("event type %q is invalid", v)
+		}
+		events = append(events, name)
+	}
+
+	backoff := watchBackoffMinimum
+	for {
+		received, err := s.listenNotification(ctx, rp, opt.Suffix, events, fn)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if _, ok := err.(watchCallbackError); ok {
+			return err
+		}
+		if _, ok := err.(watchEventError); ok {
+			return err
+		}
+		if err != nil && !isWatchRetryable(err) {
+			return err
+		}
+		// Start over if the stream worked for a while before dropped.
+		if received {
+			backoff = watchBackoffMinimum
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case <-timer.C:
+		}
+		backoff *= 2
+		if backoff > watchBackoffMaximum {
+			backoff = watchBackoffMaximum
+		}
+	}
+}
+
+// listenNotification will call fn with events until the stream is closed, and
+// return whether any event has been received.
+//
+// Errors returned by fn are wrapped in watchCallbackError, and errors of
+// decoding events are wrapped in watchEventError to tell them from errors of
+// the stream.
+func (s *Storage) listenNotification(ctx context.Context, prefix, suffix string, events []string, fn func(e Event) error) (received bool, err error) {
+	ctx, cancel := context.WithCancel(ctx)
+	infoChan := s.client.ListenBucketNotification(ctx, s.bucket, prefix, suffix, events)
+	defer func() {
+		cancel()
+		// Drain the channel so that the listening goroutine could exit.
+		for range infoChan {
+		}
+	}()
+
+	for info := range infoChan {
+		if info.Err != nil {
+			return received, info.Err
+		}
+		received = true
+		for _, v := range info.Records {
+			e, err := s.formatEvent(v)
+			if err != nil {
+				return received, watchEventError{err}
+			}
+			err = fn(e)
+			if err != nil {
+				return received, watchCallbackError{err}
+			}
+		}
+	}
+	return received, nil
+}
+
+// watchCallbackError is the error returned by the callback of Watch.
+type watchCallbackError struct {
+	err error
+}
+
+func (e watchCallbackError) Error() string {
+	return e.err.Error()
+}
+
+// watchEventError is the error of decoding an event, which will not be
+// recovered by reconnection.
+type watchEventError struct {
+	err error
+}
+
+func (e watchEventError) Error() string {
+	return fmt.Sprintf("decode event: %v", e.err)
+}
+
+func (e watchEventError) Unwrap() error {
+	return e.err
+}
+
+// isWatchRetryable checks whether the watching could be recovered by reconnection.
+func isWatchRetryable(err error) bool {
+	e := minio.ToErrorResponse(err)
+	switch {
+	case e.Code == "NotImplemented":
+		// ListenBucketNotification is not supported by the endpoint.
+		return false
+	case e.StatusCode == http.StatusTooManyRequests:
+		return true
+	case e.StatusCode >= 400 && e.StatusCode < 500:
+		// Requests like AccessDenied or NoSuchBucket will never succeed.
+		return false
+	}
+	return true
+}
+
+func (s *Storage) formatEvent(v notification.Event) (e Event, err error) {
+	// Keys in events are URL encoded.
+	key, err := url.QueryUnescape(v.S3.Object.Key)
+	if err != nil {
+		return Event{}, err
+	}
+
+	e.Name = v.EventName
+	switch {
+	case strings.HasPrefix(v.EventName, "s3:ObjectCreated:"):
+		e.Type = EventCreated
+	case strings.HasPrefix(v.EventName, "s3:ObjectRemoved:"):
+		e.Type = EventRemoved
+	case strings.HasPrefix(v.EventName, "s3:ObjectAccessed:"):
+		e.Type = EventAccessed
+	}
+	if v.EventTime != "" {
+		e.Time, err = time.Parse(time.RFC3339Nano, v.EventTime)
+		if err != nil {
+			return Event{}, err
+		}
+	}
+
+	oi := minio.ObjectInfo{
+		Key:          key,
+		ETag:         v.S3.Object.ETag,
+		Size:         v.S3.Object.Size,
+		ContentType:  v.S3.Object.ContentType,
+		UserMetadata: v.S3.Object.UserMetadata,
+		VersionID:    v.S3.Object.VersionID,
+	}
+	// User metadata in events is carried the same as list with metadata.
+	formatListedMetadata(&oi)
+	if e.Type == EventCreated {
+		oi.LastModified = e.Time
+	}
+	o, err := s.formatFileObject(oi)
+	if err != nil {
+		return Event{}, err
+	}
+	e.Object = o
+	return e, nil
+}
@@ -0,0 +1,104 @@
+package minio
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/beyondstorage/go-storage/v4/services"
+)
+
+func TestWatchCallbackError(t *testing.T) {
+	f := newFakeServer(t)
+	f.notifications = [][]string{{`{"Records":[{"eventName":"s3:ObjectCreated:Put","eventTime":"2021-01-01T00:00:00Z","s3":{"object":{"key":"w%2Fa+b","size":3}}}]}`}}
+	store := f.newStorage(t, "bucket", "/w/")
+
+	errStop := errors.New("stop")
+	var events []Event
+	err := store.Watch("", WatchOptions{}, func(e Event) error {
+		events = append(events, e)
+		return errStop
+	})
+	if err != errStop {
+		t.Errorf("watch returned %v, expected the error of the callback", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("watch received %d events, expected 1", len(events))
+	}
+	if e := events[0]; e.Type != EventCreated || e.Object.Path != "a b" || e.Object.MustGetContentLength() != 3 {
+		t.Errorf("watch received %+v with object %+v", e, e.Object)
+	}
+}
+
+func TestWatchDecodeError(t *testing.T) {
+	f := newFakeServer(t)
+	f.notifications = [][]string{{`{"Records":[{"eventName":"s3:ObjectCreated:Put","eventTime":"yesterday","s3":{"object":{"key":"w/a"}}}]}`}}
+	store := f.newStorage(t, "bucket", "/w/")
+
+	// The stream should not be reconnected, stop the test early otherwise.
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err := store.WatchWithContext(ctx, "", WatchOptions{}, func(e Event) error {
+		t.Errorf("watch received %+v, expected none", e)
+		return nil
+	})
+	if err == nil {
+		t.Errorf("watch should fail with events which could not be decoded")
+	}
+	if n := f.count("ListenBucketNotification"); n != 1 {
+		t.Errorf("watch sent %d requests, expected 1", n)
+	}
+}
+
+func TestWatchReconnect(t *testing.T) {
+	f := newFakeServer(t)
+	f.notifications = [][]string{
+		{`{"Records":[{"eventName":"s3:ObjectCreated:Put","s3":{"object":{"key":"w/a"}}}]}`},
+		{`{"Records":[{"eventName":"s3:ObjectRemoved:Delete","s3":{"object":{"key":"w/b"}}}]}`},
+	}
+	store := f.newStorage(t, "bucket", "/w/")
+
+	errStop := errors.New("stop")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	var paths []string
+	err := store.WatchWithContext(ctx, "", WatchOptions{}, func(e Event) error {
+		paths = append(paths, e.Object.Path)
+		if e.Type == EventRemoved {
+			return errStop
+		}
+		return nil
+	})
+	if err != errStop {
+		t.Errorf("watch returned %v, expected the error of the callback", err)
+	}
+	if !reflect.DeepEqual(paths, []string{"a", "b"}) {
+		t.Errorf("watch received events of %v, expected [a b]", paths)
+	}
+	if n := f.count("ListenBucketNotification"); n != 2 {
+		t.Errorf("watch sent %d requests, expected 2", n)
+	}
+}
+
+func TestWatchAccessDenied(t *testing.T) {
+	f := newFakeServer(t)
+	f.fail("ListenBucketNotification", http.StatusForbidden, "AccessDenied")
+	store := f.newStorage(t, "bucket", "/w/")
+
+	// The watching should not be retried, stop the test early otherwise.
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err := store.WatchWithContext(ctx, "", WatchOptions{}, func(e Event) error {
+		t.Errorf("watch received %+v, expected none", e)
+		return nil
+	})
+	if !errors.Is(err, services.ErrPermissionDenied) {
+		t.Errorf("watch returned %v, expected ErrPermissionDenied", err)
+	}
+	if n := f.count("ListenBucketNotification"); n != 1 {
+		t.Errorf("watch sent %d requests, expected 1", n)
+	}
+}